@@ -22,8 +22,22 @@ var algs = map[string]alg{
 	"o-o-o": {pos: "o-o-o", status: moveStatusQueenSideCastling},
 }
 
+// offBoardSides are the sides of the board that off-board positions can be on, as seen
+// from white. The edge is the file or rank (as a character) that the square must be on,
+// and dx, dy is the direction from that square to the off-board position.
+var offBoardSides = map[string]struct {
+	edge   byte
+	dx, dy int
+}{
+	"left":   {'a', -1, 0},
+	"right":  {'h', 1, 0},
+	"top":    {'8', 0, 1},
+	"bottom": {'1', 0, -1},
+}
+
 // newAlg calculates coordinates (0-7),(0-7) from a chess position string, like "C5".
-// It also handles special cases, like castling and empty strings.
+// It also handles special cases, like castling and empty strings, and off-board
+// positions, like "right:h3", that are just outside the board next to a square.
 func newAlg(s string, inverted bool) (alg, error) {
 	s = strings.ToLower(s)
 
@@ -33,6 +47,10 @@ func newAlg(s string, inverted bool) (alg, error) {
 		return fixedAlg, nil
 	}
 
+	if sideName, square, ok := strings.Cut(s, ":"); ok {
+		return newOffBoardAlg(s, sideName, square, inverted)
+	}
+
 	// Check illegal moves
 	a := alg{pos: s, status: moveStatusIllegal, inverted: inverted}
 	if len(s) != 2 {
@@ -51,8 +69,37 @@ func newAlg(s string, inverted bool) (alg, error) {
 	return a, nil
 }
 
+// newOffBoardAlg calculates the coordinates of an off-board position, like "right:h3".
+// The coordinates are outside the board (-1 or 8).
+func newOffBoardAlg(s, sideName, square string, inverted bool) (alg, error) {
+	a := alg{pos: s, status: moveStatusIllegal, inverted: inverted}
+
+	side, ok := offBoardSides[sideName]
+	if !ok {
+		return a, errors.New("invalid side in off-board alg : " + sideName)
+	}
+	edge, err := newAlg(square, false)
+	if err != nil || edge.status != moveStatusNormal {
+		return a, errors.New("invalid square in off-board alg : " + square)
+	}
+	if !strings.ContainsRune(square, rune(side.edge)) {
+		return a, fmt.Errorf("the square in off-board alg must be on the %s edge : %s", sideName, square)
+	}
+
+	a.status = moveStatusOffBoard
+	a.x = edge.x + side.dx
+	a.y = edge.y + side.dy
+
+	return a, nil
+}
+
+// isOffBoard returns true if the string is an off-board position, like "right:h3".
+func isOffBoard(s string) bool {
+	return strings.Contains(s, ":")
+}
+
 func (a alg) coords() (int, int) {
-	if a.status != moveStatusNormal {
+	if a.status != moveStatusNormal && a.status != moveStatusOffBoard {
 		// ok to panic here, it's an internal struct, and it is
 		// being used wrong!
 		panic("not a normal move, check status field")
@@ -146,3 +146,43 @@ func TestString(t *testing.T) {
 		t.Errorf("String(e4) failed: %v", got)
 	}
 }
+
+func Test_newAlgOffBoard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		s        string
+		inverted bool
+		x, y     int
+		wantErr  bool
+	}{
+		{"right:h3", false, 8, 2, false},
+		{"RIGHT:H3", false, 8, 2, false},
+		{"left:a4", false, -1, 3, false},
+		{"top:e8", false, 4, 8, false},
+		{"bottom:c1", false, 2, -1, false},
+		{"right:h3", true, -1, 5, false},
+		{"top:e8", true, 3, -1, false},
+		{"right:g3", false, 0, 0, true},
+		{"top:e7", false, 0, 0, true},
+		{"middle:e4", false, 0, 0, true},
+		{"left:a9", false, 0, 0, true},
+		{"left:", false, 0, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := newAlg(tt.s, tt.inverted)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newAlg(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got.status != moveStatusOffBoard {
+			t.Errorf("newAlg(%q) status = %v, want %v", tt.s, got.status, moveStatusOffBoard)
+		}
+		if x, y := got.coords(); x != tt.x || y != tt.y {
+			t.Errorf("newAlg(%q).coords() = %d, %d, want %d, %d", tt.s, x, y, tt.x, tt.y)
+		}
+	}
+}
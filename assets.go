@@ -0,0 +1,138 @@
+package chessImager
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Option is used to configure an Imager, when it is created.
+type Option func(*Imager)
+
+// WithFS makes the imager load all assets (board images, piece images, image maps
+// and fonts) from the file system fsys, instead of from the OS file system. The
+// asset paths in the settings are relative to the root of fsys. Since embed.FS and
+// *zip.Reader both implement fs.FS, this can be used to embed themes in the binary,
+// or to ship themes as zip files.
+func WithFS(fsys fs.FS) Option {
+	return func(i *Imager) {
+		i.assets = fsys
+	}
+}
+
+// NewImagerFromFS creates a new Imager using a settings file that is stored in the
+// file system fsys. The asset paths in the settings file are relative to the directory
+// of the settings file, inside fsys. The settings are validated, see Settings.Validate.
+func NewImagerFromFS(fsys fs.FS, name string, opts ...Option) (*Imager, error) {
+	i := newImager(opts)
+	err := i.LoadSettingsFromFS(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// LoadSettingsFromFS loads in a new settings file from the file system fsys. The
+// asset paths in the settings file are relative to the directory of the settings
+// file, inside fsys. The settings are validated, see Settings.Validate. The file
+// format is chosen by the file extension, see LoadSettings.
+func (i *Imager) LoadSettingsFromFS(fsys fs.FS, name string) error {
+	name = assetFSPath(name)
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	data, err = settingsToJSON(name, data)
+	if err != nil {
+		return err
+	}
+
+	assets, err := fs.Sub(fsys, path.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	s, err := loadDefaultSettings()
+	if err != nil {
+		return err
+	}
+	s, err = s.merge(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	c := &Imager{settings: s, assets: assets}
+	if err = s.validate(c.assetExists); err != nil {
+		return err
+	}
+
+	i.settings, i.assets, i.assetDir = s, assets, ""
+
+	return nil
+}
+
+// openAsset opens an asset, either from the asset file system (see WithFS), or from
+// the OS file system. OS paths that are not absolute are first resolved relative to
+// the directory of the settings file, and then relative to the working directory.
+// Assets that are not found there are looked up in the assets of the bundled themes,
+// so that settings that were saved from a bundled theme can be loaded again.
+func (i *Imager) openAsset(name string) (fs.File, error) {
+	var f fs.File
+	var err error
+	if i.assets != nil {
+		f, err = i.assets.Open(assetFSPath(name))
+	} else {
+		f, err = os.Open(i.assetOSPath(name))
+	}
+	if p := assetFSPath(name); err != nil && p != "." {
+		if themeFile, themeErr := themeAssets.Open(path.Join(themeAssetDir, p)); themeErr == nil {
+			return themeFile, nil
+		}
+	}
+
+	return f, err
+}
+
+// readAsset reads an entire asset, see openAsset.
+func (i *Imager) readAsset(name string) ([]byte, error) {
+	f, err := i.openAsset(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// assetExists returns true if the asset can be opened.
+func (i *Imager) assetExists(name string) bool {
+	f, err := i.openAsset(name)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+
+	return true
+}
+
+// assetOSPath returns the OS path of an asset, when the assets are loaded from the OS file system.
+func (i *Imager) assetOSPath(name string) string {
+	if i.assetDir != "" && !filepath.IsAbs(name) {
+		p := filepath.Join(i.assetDir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	return name
+}
+
+// assetFSPath converts an asset path to a valid fs.FS path.
+func assetFSPath(name string) string {
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "/")
+}
@@ -0,0 +1,123 @@
+package chessImager
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func mapFSMust(t *testing.T, files map[string]string) fstest.MapFS {
+	t.Helper()
+
+	fsys := fstest.MapFS{}
+	for name, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %v : %v", path, err)
+		}
+		fsys[name] = &fstest.MapFile{Data: data}
+	}
+	return fsys
+}
+
+func TestNewImagerFromFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFSMust(t, map[string]string{
+		"theme/settings.json":       "test/data/piecesImageMap.json",
+		"theme/pieces_colorful.png": "test/data/pieces_colorful.png",
+	})
+
+	imager, err := NewImagerFromFS(fsys, "theme/settings.json")
+	if err != nil {
+		t.Fatalf("NewImagerFromFS() failed : %v", err)
+	}
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("Render() failed : %v", err)
+	}
+
+	compareImages(t, "piecesImageMap.png", &img)
+}
+
+func TestNewImagerFromFSMissingAsset(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFSMust(t, map[string]string{
+		"settings.json": "test/data/piecesImageMap.json",
+	})
+
+	if _, err := NewImagerFromFS(fsys, "settings.json"); err == nil {
+		t.Errorf("NewImagerFromFS() returned no error for a missing asset")
+	}
+}
+
+func TestWithFSZip(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, path := range map[string]string{
+		"pieces/pieces_colorful.png": "test/data/pieces_colorful.png",
+		"fonts/roboto.ttf":           "test/data/roboto.ttf",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %v : %v", path, err)
+		}
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry : %v", err)
+		}
+		_, _ = f.Write(data)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to create zip : %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open zip : %v", err)
+	}
+
+	imager := NewImager(WithFS(zr))
+	imager.settings.Pieces.Type = piecesTypeImageMap
+	imager.settings.Pieces.ImageMap.Path = "/pieces/pieces_colorful.png"
+	imager.settings.FontStyle.Path = "fonts/roboto.ttf"
+
+	ctx := imager.NewContext("b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25")
+	ctx.AddAnnotation("e4", "!!")
+	if _, err = imager.RenderWithContext(ctx); err != nil {
+		t.Errorf("RenderWithContext() failed : %v", err)
+	}
+}
+
+func TestAssetsRelativeToSettings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("test/data/pieces_colorful.png")
+	if err != nil {
+		t.Fatalf("failed to read image : %v", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "my_pieces.png"), data, 0600); err != nil {
+		t.Fatalf("failed to write image : %v", err)
+	}
+	settings := `{"pieces": {"type": 2, "image_map": {"path": "my_pieces.png"}}}`
+	if err = os.WriteFile(filepath.Join(dir, "settings.json"), []byte(settings), 0600); err != nil {
+		t.Fatalf("failed to write settings : %v", err)
+	}
+
+	imager, err := NewImagerFromPath(filepath.Join(dir, "settings.json"))
+	if err != nil {
+		t.Fatalf("NewImagerFromPath() failed : %v", err)
+	}
+	if _, err = imager.Render("8/8/8/8/8/8/8/K6k w - - 0 1"); err != nil {
+		t.Errorf("Render() failed : %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package chessImager
+
+import (
+	"slices"
+)
+
+var (
+	knightSteps   = []square{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingSteps     = []square{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+	rookDirs      = []square{{0, 1}, {1, 0}, {0, -1}, {-1, 0}}
+	bishopDirs    = []square{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}
+	queenDirs     = append(slices.Clone(rookDirs), bishopDirs...)
+	pawnCaptureDx = []int{-1, 1}
+)
+
+// attackers returns the squares of the pieces of one side that attack a square, whether
+// the square is empty or not. Pieces only attack through empty squares.
+func (p *position) attackers(s square, white bool) []square {
+	var result []square
+	isAttacker := func(from square, kinds ...chessPiece) {
+		piece := p.pieceAt(from)
+		if piece == noPiece || isWhite(piece) != white {
+			return
+		}
+		for _, kind := range kinds {
+			if pieceKind(piece) == kind {
+				result = append(result, from)
+				return
+			}
+		}
+	}
+
+	// Pawns attack diagonally forward, so the attacking pawns are diagonally behind the square
+	dy := -1
+	if !white {
+		dy = 1
+	}
+	for _, dx := range pawnCaptureDx {
+		if from := (square{s.x + dx, s.y + dy}); from.onBoard() {
+			isAttacker(from, whitePawn)
+		}
+	}
+
+	for _, step := range knightSteps {
+		if from := (square{s.x + step.x, s.y + step.y}); from.onBoard() {
+			isAttacker(from, whiteKnight)
+		}
+	}
+	for _, step := range kingSteps {
+		if from := (square{s.x + step.x, s.y + step.y}); from.onBoard() {
+			isAttacker(from, whiteKing)
+		}
+	}
+
+	slide := func(dirs []square, kind chessPiece) {
+		for _, dir := range dirs {
+			from := square{s.x + dir.x, s.y + dir.y}
+			for from.onBoard() && p.pieceAt(from) == noPiece {
+				from = square{from.x + dir.x, from.y + dir.y}
+			}
+			if from.onBoard() {
+				isAttacker(from, kind, whiteQueen)
+			}
+		}
+	}
+	slide(rookDirs, whiteRook)
+	slide(bishopDirs, whiteBishop)
+
+	return result
+}
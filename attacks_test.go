@@ -0,0 +1,69 @@
+package chessImager
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_attackers(t *testing.T) {
+	t.Parallel()
+
+	const fen = "r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 1"
+	pos, err := newPosition(fen)
+	if err != nil {
+		t.Fatalf("newPosition() failed : %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		square string
+		white  bool
+		want   []string
+	}{
+		{"e5 by white", "e5", true, []string{"f3"}},
+		{"e5 by black", "e5", false, []string{"c6"}},
+		{"f7 by white", "f7", true, []string{"c4"}},
+		{"f7 by black", "f7", false, []string{"e8"}},
+		{"d4 by white", "d4", true, []string{"f3"}},
+		{"d4 by black", "d4", false, []string{"c6", "e5"}},
+		{"f1 by white", "f1", true, []string{"c4", "e1", "h1"}},
+		{"d5 by black", "d5", false, []string{"f6"}},
+		{"e7 by black", "e7", false, []string{"c6", "d8", "e8", "f8"}},
+		{"a5 by white", "a5", true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newAlg(tt.square, false)
+			var got []string
+			for _, s := range pos.attackers(square{a.x, a.y}, tt.white) {
+				got = append(got, s.String())
+			}
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("attackers(%s) = %v, want %v", tt.square, got, want)
+			}
+		})
+	}
+}
+
+func Test_colormapAt(t *testing.T) {
+	t.Parallel()
+
+	colors := colormaps["diverging"]
+	if got := colormapAt(colors, 0); got != colors[0] {
+		t.Errorf("colormapAt(0) = %v, want %v", got, colors[0])
+	}
+	if got := colormapAt(colors, 0.5); got != colors[2] {
+		t.Errorf("colormapAt(0.5) = %v, want %v", got, colors[2])
+	}
+	if got := colormapAt(colors, 2); got != colors[4] {
+		t.Errorf("colormapAt(2) = %v, want %v", got, colors[4])
+	}
+	if got := colormapAt(colors, math.NaN()); got != colors[0] {
+		t.Errorf("colormapAt(NaN) = %v, want %v", got, colors[0])
+	}
+}
@@ -56,13 +56,8 @@ func TestBoardImage(t *testing.T) {
 func TestBoardImageInvalid(t *testing.T) {
 	t.Parallel()
 
-	imager, err := NewImagerFromPath("test/data/boardInvalidImagePath.json")
-	if err != nil {
-		t.Errorf("Failed to load JSON file: %v", err)
-	}
-
-	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
-	_, err = imager.Render(fen)
+	// Missing image paths are reported when the settings are loaded
+	_, err := NewImagerFromPath("test/data/boardInvalidImagePath.json")
 	if err == nil {
 		t.Errorf("boardInvalidImagePath did not fail")
 	}
@@ -127,13 +122,8 @@ func TestInvalidSetOrderDuplicateIndex(t *testing.T) {
 func TestInvalidSetOrderJson(t *testing.T) {
 	t.Parallel()
 
-	imager, err := NewImagerFromPath("test/data/boardInvalidOrder.json")
-	if err != nil {
-		t.Errorf("Failed to load JSON file: %v", err)
-	}
-
-	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
-	_, err = imager.Render(fen)
+	// An invalid order is reported when the settings are loaded
+	_, err := NewImagerFromPath("test/data/boardInvalidOrder.json")
 	if err == nil {
 		t.Errorf("boardInvalidOrder did not fail")
 	}
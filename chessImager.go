@@ -1,18 +1,23 @@
 package chessImager
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/fogleman/gg"
 	"github.com/golang/freetype/truetype"
+	"github.com/nfnt/resize"
 	"golang.org/x/exp/constraints"
 	"golang.org/x/image/font/gofont/goregular"
 )
@@ -31,35 +36,68 @@ type Imager struct {
 	useInternalFont bool
 	settings        *Settings
 	inverted        bool
+	// Render-time scale factor, see ImageContext.Scale
+	scale float64
+	// The file system that assets are loaded from, see WithFS
+	assets fs.FS
+	// The directory of the settings file, used to resolve relative asset paths
+	assetDir string
 }
 
 // NewImager creates a new Imager.
-func NewImager() *Imager {
+func NewImager(opts ...Option) *Imager {
+	i := newImager(opts)
 	// We ignore the error here, since the default embedded settings file
 	// should always be correct.
-	s, _ := loadDefaultSettings()
+	i.settings, _ = loadDefaultSettings()
 
-	return &Imager{settings: s}
+	return i
 }
 
-// NewImagerFromPath creates a new Imager using a user-defined JSON file.
-func NewImagerFromPath(path string) (i *Imager, err error) {
-	s, err := loadSettings(path)
+// newImager creates a new Imager without settings, and applies the options.
+func newImager(opts []Option) *Imager {
+	i := &Imager{}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// NewImagerFromPath creates a new Imager using a user-defined JSON, YAML or TOML file.
+// The file is merged on top of the default settings, so it only needs to
+// contain the settings that differ from the defaults. The settings are
+// validated, see Settings.Validate. Relative asset paths are resolved
+// relative to the directory of the settings file, or, if the asset is
+// not found there, relative to the working directory.
+func NewImagerFromPath(path string, opts ...Option) (i *Imager, err error) {
+	i = newImager(opts)
+	err = i.LoadSettings(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Imager{settings: s}, nil
+	return i, nil
 }
 
-// LoadSettings loads in a new settings file.
+// LoadSettings loads in a new settings file. The file is merged on top of the
+// default settings, so it only needs to contain the settings that differ from
+// the defaults. Use ApplySettings to merge a file on top of the current settings.
+// The settings are validated, see Settings.Validate. Files ending with .yaml or .yml
+// are read as YAML, files ending with .toml as TOML, and all other files as JSON.
 func (i *Imager) LoadSettings(path string) error {
 	s, err := loadSettings(path)
 	if err != nil {
 		return err
 	}
 
-	i.settings = s
+	c := *i
+	c.settings, c.assetDir = s, filepath.Dir(path)
+	if err = s.validate(c.assetExists); err != nil {
+		return err
+	}
+
+	i.settings, i.assetDir = s, c.assetDir
 
 	return nil
 }
@@ -86,19 +124,69 @@ func (i *Imager) RenderWithContextInverted(ctx *ImageContext) (image.Image, erro
 	return i.renderWithContext(ctx)
 }
 
+// RenderSized renders an image of a chess board based on an image context, where the board, border and rank
+// and file indicators are scaled to fit inside an image of the given width and height. The board is centered
+// in the image, and any remaining space is transparent. The Scale field of the context is ignored.
+// An error is returned if the size is too small for the board to be legible.
+func (i *Imager) RenderSized(ctx *ImageContext, width, height int) (image.Image, error) {
+	i.inverted = false
+	return i.renderSized(ctx, width, height)
+}
+
+// RenderSizedInverted renders an image of an inverted chess board based on an image context,
+// scaled to fit inside an image of the given width and height. See RenderSized for details.
+func (i *Imager) RenderSizedInverted(ctx *ImageContext, width, height int) (image.Image, error) {
+	i.inverted = true
+	return i.renderSized(ctx, width, height)
+}
+
+// renderSized renders an image of a chess board based on an image context, scaled to fit inside width x height.
+func (i *Imager) renderSized(ctx *ImageContext, width, height int) (image.Image, error) {
+	scale, err := i.getFitScale(width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	c := *ctx
+	c.Scale = scale
+	img, err := i.renderWithContext(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Center the board in the requested size
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	offset := image.Pt((width-img.Bounds().Dx())/2, (height-img.Bounds().Dy())/2)
+	draw.Draw(dst, img.Bounds().Sub(img.Bounds().Min).Add(offset), img, img.Bounds().Min, draw.Src)
+
+	return dst, nil
+}
+
 // renderWithContext renders an image of a chess board based on an image context.
 func (i *Imager) renderWithContext(ctx *ImageContext) (image.Image, error) {
 	if ok := validateFen(ctx.Fen); !ok {
 		return nil, fmt.Errorf("invalid fen: %v", ctx.Fen)
 	}
+	if err := ctx.validateStyles(); err != nil {
+		return nil, err
+	}
+
+	si, err := i.withOverlays(ctx.SettingsOverlays)
+	if err != nil {
+		return nil, err
+	}
+	si, err = si.withScale(ctx.Scale)
+	if err != nil {
+		return nil, err
+	}
 
-	size, err := i.getBoardSize()
+	size, err := si.getBoardSize()
 	if err != nil {
 		return nil, err
 	}
 	c := gg.NewContextForImage(image.NewRGBA(size))
 
-	r, err := i.getRenderers(c, ctx)
+	r, err := si.getRenderers(c, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +205,7 @@ func (i *Imager) renderWithContext(ctx *ImageContext) (image.Image, error) {
 // * Add annotations
 // * Add moves
 func (i *Imager) NewContext(fen string) *ImageContext {
-	return &ImageContext{Fen: fen}
+	return &ImageContext{Fen: fen, palette: i.settings.Palette}
 }
 
 // SetOrder can be used to set the render order.
@@ -199,15 +287,9 @@ func (i *Imager) getBoardSize() (image.Rectangle, error) {
 			},
 		}, nil
 	case boardTypeImage:
-		f, err := os.Open(i.settings.Board.Image.Path)
-		if err != nil {
-			return image.Rectangle{}, fmt.Errorf("failed to load image : %v", err)
-		}
-		defer f.Close()
-
-		img, _, err := image.Decode(f)
+		img, err := i.loadBoardImage()
 		if err != nil {
-			return image.Rectangle{}, fmt.Errorf("failed to encode image : %v", err)
+			return image.Rectangle{}, err
 		}
 
 		return img.Bounds(), nil
@@ -217,6 +299,29 @@ func (i *Imager) getBoardSize() (image.Rectangle, error) {
 	}
 }
 
+// loadBoardImage loads the background image of the chess board (Board.Type=1),
+// and resizes it according to the current scale factor.
+func (i *Imager) loadBoardImage() (image.Image, error) {
+	f, err := i.openAsset(i.settings.Board.Image.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image : %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image : %v", err)
+	}
+
+	if i.getScale() != 1 {
+		size := img.Bounds().Size()
+		img = resize.Resize(uint(scaleInt(size.X, i.getScale())), uint(scaleInt(size.Y, i.getScale())),
+			img, resize.Lanczos3)
+	}
+
+	return img, nil
+}
+
 func (i *Imager) setFontFace(c *gg.Context, size int) error {
 	if i.settings.FontStyle.Path == "" {
 		// Use standard font
@@ -225,12 +330,26 @@ func (i *Imager) setFontFace(c *gg.Context, size int) error {
 			return err
 		}
 
+		face := truetype.NewFace(font, &truetype.Options{Size: float64(size)})
+		c.SetFontFace(face)
+		i.useInternalFont = true
+	} else if i.assets != nil {
+		// Load font specified in config file, from the asset file system
+		data, err := i.readAsset(i.settings.FontStyle.Path)
+		if err != nil {
+			return fmt.Errorf("failed to load font face : %v", err)
+		}
+		font, err := truetype.Parse(data)
+		if err != nil {
+			return fmt.Errorf("failed to load font face : %v", err)
+		}
+
 		face := truetype.NewFace(font, &truetype.Options{Size: float64(size)})
 		c.SetFontFace(face)
 		i.useInternalFont = true
 	} else {
 		// Load font specified in config file
-		err := c.LoadFontFace(i.settings.FontStyle.Path, float64(size))
+		err := c.LoadFontFace(i.assetOSPath(i.settings.FontStyle.Path), float64(size))
 		if err != nil {
 			return fmt.Errorf("failed to load font face : %v", err)
 		}
@@ -282,15 +401,45 @@ func (i *Imager) getSquareBox(x, y int) Rectangle {
 	}
 }
 
-// loadSettings loads the settings from a json file
+// getBoardSquareBox returns the rectangle of a square in a position.
+func (i *Imager) getBoardSquareBox(s square) Rectangle {
+	if i.inverted {
+		return i.getSquareBox(invert(s.x), invert(s.y))
+	}
+	return i.getSquareBox(s.x, s.y)
+}
+
+// getAlgBox returns the rectangle of a square. For off-board positions, it returns the part
+// of the area just outside the board (normally the border) that is next to the square,
+// within an image of the given size.
+func (i *Imager) getAlgBox(a alg, width, height int) Rectangle {
+	box := i.getSquareBox(a.coords())
+	if a.status != moveStatusOffBoard {
+		return box
+	}
+
+	return box.clip(Rectangle{Width: float64(width), Height: float64(height)})
+}
+
+// loadSettings loads the settings from a JSON, YAML or TOML file, on top of the default settings,
+// so the file only needs to contain the settings that differ from the defaults.
 // Path : The path to load the settings from.
 func loadSettings(path string) (*Settings, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = settingsToJSON(path, data)
 	if err != nil {
 		return nil, err
 	}
 
-	return decodeSettings(f)
+	s, err := loadDefaultSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.merge(bytes.NewReader(data))
 }
 
 // loadDefaultSettings loads the embedded default settings
@@ -300,13 +449,19 @@ func loadDefaultSettings() (*Settings, error) {
 	return decodeSettings(r)
 }
 
-// decodeSettings decode the string/file and returns a Settings object and an error
+// decodeSettings decode the string/file and returns a Settings object and an error.
+// Unknown fields are rejected, and palette references are resolved.
 func decodeSettings(r io.Reader) (*Settings, error) {
 	s := &Settings{}
-	err := json.NewDecoder(r).Decode(s)
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	err := d.Decode(s)
 	if err != nil {
 		return nil, err
 	}
+	if err = s.resolvePalette(); err != nil {
+		return nil, err
+	}
 
 	return s, nil
 }
@@ -5,46 +5,220 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
+	"strconv"
 	"strings"
+
+	"golang.org/x/image/colornames"
 )
 
+// ColorRGBA is a color, that is marshaled to a #rrggbbaa string. When unmarshaled,
+// all the formats supported by parseColor are accepted. Palette references ("$name")
+// are kept, and marshaled as references, so that the color follows changes to the
+// palette. They are resolved when the settings are loaded, see Settings.resolvePalette.
 type ColorRGBA struct {
 	color.RGBA
+	// ref is the palette reference of the color, or "" if the color is not a reference
+	ref string
 }
 
-func (c *ColorRGBA) MarshalJSON() ([]byte, error) {
+func (c ColorRGBA) MarshalJSON() ([]byte, error) {
+	if c.ref != "" {
+		return json.Marshal(c.ref)
+	}
+
 	// Encode the color.RGBA as a hexadecimal string
 	hexColor := fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
 	return json.Marshal(hexColor)
 }
 
 func (c *ColorRGBA) UnmarshalJSON(data []byte) (err error) {
-	var hexColor string
+	var s string
 
-	if err = json.Unmarshal(data, &hexColor); err != nil {
+	if err = json.Unmarshal(data, &s); err != nil {
 		return err
 	}
 
-	// Catch invalid colors
-	if len(hexColor) < 6 || len(hexColor) > 9 {
-		return errors.New("invalid color")
+	if s = strings.TrimSpace(s); strings.HasPrefix(s, "$") {
+		c.RGBA, c.ref = color.RGBA{}, s
+		return nil
+	}
+	c.RGBA, c.ref = color.RGBA{}, ""
+	c.RGBA, err = parseColor(s, nil)
+
+	return err
+}
+
+func (c *ColorRGBA) toRGBA() (float64, float64, float64, float64) {
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255
+}
+
+// parseColor converts a string to a color. The following formats are supported:
+//
+//	#RRGGBBAA, #RRGGBB, #RGBA and #RGB (the # is optional)
+//	CSS color names, like "red", "rebeccapurple" and "transparent"
+//	rgb(r, g, b) and rgba(r, g, b, a), where r, g and b are 0-255 or percentages
+//	hsl(h, s, l) and hsla(h, s, l, a), where h is in degrees, and s and l are percentages
+//	$name, a reference to a color in the palette
+//
+// The alpha value a is 0-1 or a percentage. The CSS4 space separated syntax, like
+// rgb(255 0 0 / 50%), is also supported.
+func parseColor(s string, palette map[string]ColorRGBA) (color.RGBA, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+
+	if name, ok := strings.CutPrefix(s, "$"); ok {
+		col, ok := palette[name]
+		if !ok {
+			return color.RGBA{}, fmt.Errorf("invalid color (%s) : unknown palette color", s)
+		}
+		return col.RGBA, nil
+	}
+
+	if lower == "transparent" {
+		return color.RGBA{}, nil
+	}
+	if lower == "rebeccapurple" {
+		return color.RGBA{R: 0x66, G: 0x33, B: 0x99, A: 0xff}, nil
+	}
+	if col, ok := colornames.Map[lower]; ok {
+		return col, nil
+	}
+
+	if name, args, ok := strings.Cut(lower, "("); ok && strings.HasSuffix(args, ")") {
+		col, err := parseColorFunction(name, strings.TrimSuffix(args, ")"))
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("invalid color (%s) : %v", s, err)
+		}
+		return col, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		// Expand the short forms #RGB and #RGBA
+		var long strings.Builder
+		for _, r := range hex {
+			long.WriteRune(r)
+			long.WriteRune(r)
+		}
+		hex = long.String()
 	}
 
-	// Remove the # and add the alpha if needed
-	hexColor = strings.TrimPrefix(hexColor, "#")
-	if len(hexColor) == 6 {
-		hexColor += "FF"
+	return hexToRGBA(hex)
+}
+
+// parseColorFunction parses the arguments of a rgb(), rgba(), hsl() or hsla() color.
+func parseColorFunction(name, args string) (color.RGBA, error) {
+	var values []string
+	if strings.Contains(args, ",") {
+		values = strings.Split(args, ",")
+	} else {
+		// CSS4 syntax : rgb(r g b / a)
+		main, alpha, hasAlpha := strings.Cut(args, "/")
+		values = strings.Fields(main)
+		if hasAlpha {
+			values = append(values, alpha)
+		}
+	}
+	for n := range values {
+		values[n] = strings.TrimSpace(values[n])
+	}
+
+	if len(values) != 3 && len(values) != 4 {
+		return color.RGBA{}, fmt.Errorf("%s() needs 3 or 4 values, got %d", name, len(values))
 	}
 
-	// Parse the hexadecimal string and set it to the color.RGBA
-	_, err = fmt.Sscanf(hexColor, "%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	alpha := 1.0
+	if len(values) == 4 {
+		a, err := parseColorValue(values[3], 1)
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		alpha = a
+	}
+
+	var r, g, b float64
+	switch name {
+	case "rgb", "rgba":
+		for n, v := range []*float64{&r, &g, &b} {
+			c, err := parseColorValue(values[n], 255)
+			if err != nil {
+				return color.RGBA{}, err
+			}
+			*v = c / 255
+		}
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(values[0], "deg"), 64)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("invalid hue %q", values[0])
+		}
+		if !strings.HasSuffix(values[1], "%") || !strings.HasSuffix(values[2], "%") {
+			return color.RGBA{}, errors.New("saturation and lightness must be percentages")
+		}
+		sat, err := parseColorValue(values[1], 1)
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		light, err := parseColorValue(values[2], 1)
+		if err != nil {
+			return color.RGBA{}, err
+		}
+		r, g, b = hslToRGB(h, sat, light)
+	default:
+		return color.RGBA{}, fmt.Errorf("unknown color function %s()", name)
+	}
+
+	// color.RGBA uses premultiplied alpha, but like for hex colors, we store the
+	// color components as they are specified.
+	return color.RGBA{
+		R: uint8(math.Round(r * 255)),
+		G: uint8(math.Round(g * 255)),
+		B: uint8(math.Round(b * 255)),
+		A: uint8(math.Round(alpha * 255)),
+	}, nil
+}
+
+// parseColorValue parses a number or a percentage, where 100% equals max.
+// The value is clamped to 0-max.
+func parseColorValue(s string, max float64) (float64, error) {
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if percent {
+		v = v * max / 100
 	}
 
-	return nil
+	return math.Max(0, math.Min(max, v)), nil
 }
 
-func (c *ColorRGBA) toRGBA() (float64, float64, float64, float64) {
-	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255
+// hslToRGB converts a hue (in degrees), saturation and lightness (0-1) to red, green and blue (0-1).
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return r + m, g + m, b + m
 }
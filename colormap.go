@@ -0,0 +1,91 @@
+package chessImager
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// colormaps are the named colormaps, with colors from the lowest to the highest value
+var colormaps = map[string][]color.RGBA{
+	// Red for low values, white in the middle and blue for high values
+	"diverging": {
+		{R: 0xB2, G: 0x18, B: 0x2B, A: 0xFF},
+		{R: 0xEF, G: 0x8A, B: 0x62, A: 0xFF},
+		{R: 0xF7, G: 0xF7, B: 0xF7, A: 0xFF},
+		{R: 0x67, G: 0xA9, B: 0xCF, A: 0xFF},
+		{R: 0x21, G: 0x66, B: 0xAC, A: 0xFF},
+	},
+	// Dark purple for low values, through blue and green, to yellow for high values
+	"viridis": {
+		{R: 0x44, G: 0x01, B: 0x54, A: 0xFF},
+		{R: 0x47, G: 0x2D, B: 0x7B, A: 0xFF},
+		{R: 0x3B, G: 0x52, B: 0x8B, A: 0xFF},
+		{R: 0x2C, G: 0x72, B: 0x8E, A: 0xFF},
+		{R: 0x21, G: 0x91, B: 0x8C, A: 0xFF},
+		{R: 0x28, G: 0xAE, B: 0x80, A: 0xFF},
+		{R: 0x5E, G: 0xC9, B: 0x62, A: 0xFF},
+		{R: 0xAD, G: 0xDC, B: 0x30, A: 0xFF},
+		{R: 0xFD, G: 0xE7, B: 0x25, A: 0xFF},
+	},
+	// Black for low values, through purple and red, to pale yellow for high values
+	"magma": {
+		{R: 0x00, G: 0x00, B: 0x04, A: 0xFF},
+		{R: 0x1C, G: 0x10, B: 0x44, A: 0xFF},
+		{R: 0x4F, G: 0x12, B: 0x7B, A: 0xFF},
+		{R: 0x81, G: 0x25, B: 0x81, A: 0xFF},
+		{R: 0xB5, G: 0x36, B: 0x7A, A: 0xFF},
+		{R: 0xE5, G: 0x50, B: 0x64, A: 0xFF},
+		{R: 0xFB, G: 0x88, B: 0x61, A: 0xFF},
+		{R: 0xFE, G: 0xC2, B: 0x87, A: 0xFF},
+		{R: 0xFC, G: 0xFD, B: 0xBF, A: 0xFF},
+	},
+}
+
+// colormapNames returns the names of the colormaps, sorted.
+func colormapNames() []string {
+	var names []string
+	for name := range colormaps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// getColormap returns the colors of the style, from the lowest to the highest value.
+func (s *HeatmapStyle) getColormap() ([]color.RGBA, error) {
+	if len(s.Colors) > 0 {
+		colors := make([]color.RGBA, len(s.Colors))
+		for n, c := range s.Colors {
+			colors[n] = c.RGBA
+		}
+		return colors, nil
+	}
+
+	colors, ok := colormaps[s.Colormap]
+	if !ok {
+		return nil, fmt.Errorf("unknown colormap : %q", s.Colormap)
+	}
+
+	return colors, nil
+}
+
+// colormapAt returns the color at t (0-1) in a colormap, interpolated between the
+// two closest colors. Values outside 0-1 are clamped, and NaN gives the first color.
+func colormapAt(colors []color.RGBA, t float64) color.RGBA {
+	if len(colors) == 1 || math.IsNaN(t) {
+		return colors[0]
+	}
+
+	t = math.Max(0, math.Min(1, t)) * float64(len(colors)-1)
+	n := min(int(t), len(colors)-2)
+	f := t - float64(n)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*f))
+	}
+	a, b := colors[n], colors[n+1]
+
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
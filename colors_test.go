@@ -1,8 +1,10 @@
 package chessImager
 
 import (
+	"bytes"
 	"image/color"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -157,3 +159,132 @@ func TestColorRGBA_ToRGBA(t *testing.T) {
 		t.Errorf("failed to unmarshal color Alpha (%v!=0.5019607843137255)", a)
 	}
 }
+
+func Test_parseColor(t *testing.T) {
+	t.Parallel()
+
+	palette := map[string]ColorRGBA{"accent": {RGBA: color.RGBA{R: 1, G: 2, B: 3, A: 4}}}
+	tests := []struct {
+		s       string
+		wantCol color.RGBA
+		wantErr bool
+	}{
+		{s: "#F00", wantCol: color.RGBA{R: 255, A: 255}},
+		{s: "#f008", wantCol: color.RGBA{R: 255, A: 136}},
+		{s: "0F0", wantCol: color.RGBA{G: 255, A: 255}},
+		{s: "#9D6B5E", wantCol: color.RGBA{R: 157, G: 107, B: 94, A: 255}},
+		{s: "red", wantCol: color.RGBA{R: 255, A: 255}},
+		{s: " DarkSlateGray ", wantCol: color.RGBA{R: 47, G: 79, B: 79, A: 255}},
+		{s: "rebeccapurple", wantCol: color.RGBA{R: 102, G: 51, B: 153, A: 255}},
+		{s: "transparent", wantCol: color.RGBA{}},
+		{s: "rgb(255, 128, 0)", wantCol: color.RGBA{R: 255, G: 128, A: 255}},
+		{s: "rgba(255, 128, 0, 0.5)", wantCol: color.RGBA{R: 255, G: 128, A: 128}},
+		{s: "rgb(100%, 50%, 0%)", wantCol: color.RGBA{R: 255, G: 128, A: 255}},
+		{s: "rgb(255 128 0 / 25%)", wantCol: color.RGBA{R: 255, G: 128, A: 64}},
+		{s: "RGB(300, -5, 0)", wantCol: color.RGBA{R: 255, A: 255}},
+		{s: "hsl(120, 100%, 50%)", wantCol: color.RGBA{G: 255, A: 255}},
+		{s: "hsl(240deg 100% 25%)", wantCol: color.RGBA{B: 128, A: 255}},
+		{s: "hsla(0, 0%, 100%, 0.5)", wantCol: color.RGBA{R: 255, G: 255, B: 255, A: 128}},
+		{s: "hsl(-120, 100%, 50%)", wantCol: color.RGBA{B: 255, A: 255}},
+		{s: "$accent", wantCol: color.RGBA{R: 1, G: 2, B: 3, A: 4}},
+		{s: "$missing", wantErr: true},
+		{s: "notacolor", wantErr: true},
+		{s: "#12345", wantErr: true},
+		{s: "rgb(1, 2)", wantErr: true},
+		{s: "rgb(a, b, c)", wantErr: true},
+		{s: "hsl(0, 1, 1)", wantErr: true},
+		{s: "cmyk(0, 0, 0, 0)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			gotCol, err := parseColor(tt.s, palette)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseColor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && gotCol != tt.wantCol {
+				t.Errorf("parseColor() gotCol = %v, want %v", gotCol, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestPalette(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(`{
+		"palette": {"accent": "rgb(157, 107, 94)", "arrow": "$accent"},
+		"border": {"color": "$accent"},
+		"move_style": {"color": "$arrow", "color2": "navy"}
+	}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	want := hexMust(t, "#9D6B5E")
+	if imager.settings.Border.Color.RGBA != want || imager.settings.MoveStyle.Color.RGBA != want {
+		t.Errorf("palette colors were not resolved : %v, %v", imager.settings.Border.Color, imager.settings.MoveStyle.Color)
+	}
+	if imager.settings.MoveStyle.Color2.RGBA != hexMust(t, "#000080") {
+		t.Errorf("named color was not parsed : %v", imager.settings.MoveStyle.Color2)
+	}
+
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+	s, err := ctx.NewMoveStyle(MoveTypeArrow, "$accent", "#9D6B5E", 0.2, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	if !validateColor(s) {
+		t.Errorf("NewMoveStyle() did not use the palette : %v", s.Color)
+	}
+
+	for _, overlay := range []string{
+		`{"border": {"color": "$missing"}}`,
+		`{"palette": {"a": "$b", "b": "$a"}, "border": {"color": "$a"}}`,
+		`{"palette": {"a": "#GGGGGG"}}`,
+	} {
+		if err = imager.ApplySettings(strings.NewReader(overlay)); err == nil {
+			t.Errorf("ApplySettings(%s) returned no error", overlay)
+		}
+	}
+}
+
+func TestPaletteReferences(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(`{
+		"palette": {"accent": "#9D6B5E"},
+		"border": {"color": "$accent"}
+	}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	// Changing the palette later, must re-color the fields that reference it
+	err = imager.ApplySettings(strings.NewReader(`{"palette": {"accent": "#112233"}}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	if got, want := imager.settings.Border.Color.RGBA, hexMust(t, "#112233"); got != want {
+		t.Errorf("border color got = %v, want %v", got, want)
+	}
+
+	// Saving the settings must keep the reference
+	var buf bytes.Buffer
+	if err = imager.SaveSettings(&buf); err != nil {
+		t.Fatalf("SaveSettings() failed : %v", err)
+	}
+	if !strings.Contains(buf.String(), `"color": "$accent"`) {
+		t.Errorf("SaveSettings() lost the palette reference :\n%s", buf.String())
+	}
+
+	loaded := NewImager()
+	if err = loaded.ApplySettings(&buf); err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	if !reflect.DeepEqual(loaded.settings, imager.settings) {
+		t.Errorf("loaded settings got = %+v, want %+v", loaded.settings, imager.settings)
+	}
+}
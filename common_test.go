@@ -2,6 +2,7 @@ package chessImager
 
 import (
 	"image"
+	"image/color"
 	"image/png"
 	"os"
 	"testing"
@@ -61,3 +62,13 @@ func saveImage(filename string, img image.Image) {
 		panic(err)
 	}
 }
+
+func hexMust(t *testing.T, hex string) color.RGBA {
+	t.Helper()
+
+	col, err := hexToRGBA(hex)
+	if err != nil {
+		t.Fatalf("invalid color %v : %v", hex, err)
+	}
+	return col
+}
@@ -17,6 +17,20 @@ const (
 	MoveTypeArrow
 )
 
+type MoveLabelPosition int
+
+const (
+	MoveLabelPositionMiddle MoveLabelPosition = iota
+	MoveLabelPositionHead
+)
+
+type MoveHeadType int
+
+const (
+	MoveHeadTypeFilled MoveHeadType = iota
+	MoveHeadTypeOpen
+)
+
 type HighlightType int
 
 const (
@@ -75,6 +89,15 @@ const (
 	rankAndFileTypeInSquares
 )
 
+type fillType int
+
+const (
+	fillTypeLinearGradient fillType = iota
+	fillTypeRadialGradient
+	fillTypeTexture
+	fillTypeNoise
+)
+
 type direction int
 
 const (
@@ -102,6 +125,7 @@ const (
 	moveStatusEmpty
 	moveStatusKingSideCastling
 	moveStatusQueenSideCastling
+	moveStatusOffBoard
 	moveStatusIllegal = 99
 )
 
@@ -113,3 +137,17 @@ const (
 	blackKingSideCastling  castlingStatus = iota
 	blackQueenSideCastling castlingStatus = iota
 )
+
+// Format is an image format that a chess board image can be encoded to, see Imager.Encode.
+type Format int
+
+const (
+	// FormatPNG encodes the image as a PNG
+	FormatPNG Format = iota
+	// FormatJPEG encodes the image as a JPEG, see EncodeOptions.JPEGQuality
+	FormatJPEG
+	// FormatGIF encodes the image as a GIF, see EncodeOptions.GIFPalette
+	FormatGIF
+	// FormatWebP encodes the image as a lossless WebP
+	FormatWebP
+)
@@ -1,6 +1,10 @@
 package chessImager
 
-import "image"
+import (
+	"image"
+	"image/color"
+	"strings"
+)
 
 //
 // ImageContext is used for advanced chess images
@@ -13,11 +17,34 @@ type ImageContext struct {
 	pieces         map[chessPiece]image.Image
 	pieceMap       map[string]chessPiece
 	embeddedPieces []PieceRectangle
+	// Palette colors that can be referenced in the New*Style functions
+	palette map[string]ColorRGBA
 
 	Fen         string
 	Highlight   []HighlightedSquare
 	Moves       []Move
 	Annotations []Annotation
+	// Pieces are styled pieces and ghost pieces, rendered by the piece renderer
+	Pieces []StyledPiece
+	// AttackHeatmap shows how many white and black pieces attack each square, see ShowAttackHeatmap
+	AttackHeatmap *AttackHeatmap
+	// SquareValues is a heatmap of arbitrary values, see SetSquareValues
+	SquareValues *SquareValues
+	// TacticalHints shows hanging pieces, pins, discovered attacks and forks, see ShowTactics
+	TacticalHints *TacticalHints
+	// LegalMoves shows where a piece can move, see ShowLegalMoves
+	LegalMoves *LegalMoves
+	// LastMove highlights the move from a previous position, see AddLastMoveFromPositions
+	LastMove *LastMove
+
+	// Scale multiplies every geometric setting (board size, border width,
+	// font sizes, widths, paddings etc.) when rendering, so that the same
+	// settings can be used to render 1x, 2x and 3x images. 0 means 1.
+	Scale float64
+
+	// SettingsOverlays are partial JSON settings documents that are deep
+	// merged, in order, into the imager settings for this render only.
+	SettingsOverlays []string
 }
 
 // AddHighlight adds a new highlighted square.
@@ -62,15 +89,153 @@ func (c *ImageContext) AddMoveWithStyle(from, to string, style *MoveStyle) *Imag
 	return c
 }
 
-// NewHighlightStyle creates a new highlight style.
+// AddPieceStyle renders the piece on a square (as specified in the FEN string) with a specific
+// style, for example faded, tinted or in grayscale. If style is nil, the piece is rendered faded.
+func (c *ImageContext) AddPieceStyle(square string, style *PieceStyle) *ImageContext {
+	c.Pieces = append(c.Pieces, StyledPiece{Square: square, Style: style})
+
+	return c
+}
+
+// AddGhostPiece adds a piece that is not in the FEN string, for example to show where a piece
+// came from, or a hypothetical move. The piece is a piece code, like "WN" or "bq", and it is
+// rendered on top of the FEN pieces. If style is nil, the piece is rendered faded.
+func (c *ImageContext) AddGhostPiece(square, piece string, style *PieceStyle) *ImageContext {
+	c.Pieces = append(c.Pieces, StyledPiece{Square: square, Piece: piece, Style: style})
+
+	return c
+}
+
+// AddLabeledMove adds a move with a label, like "1", "+0.45" or "42%", and a weight (0-1)
+// that makes the move thinner and/or more transparent, as specified by the move style.
+// Use a weight of 1 for a move at full strength. This can be used to show a ranked set of
+// candidate moves.
+func (c *ImageContext) AddLabeledMove(from, to, label string, weight float64) *ImageContext {
+	return c.AddLabeledMoveWithStyle(from, to, label, weight, nil)
+}
+
+// AddLabeledMoveWithStyle adds a move with a label and a weight, with a specific style.
+// See AddLabeledMove.
+func (c *ImageContext) AddLabeledMoveWithStyle(from, to, label string, weight float64, style *MoveStyle) *ImageContext {
+	c.Moves = append(c.Moves, Move{From: from, To: to, Label: label, Weight: &weight, Style: style})
+
+	return c
+}
+
+// AddMovePath adds a move that passes through several squares, separated by dashes,
+// for example "a1-a4-h4" to show a plan for a rook manoeuvre.
+func (c *ImageContext) AddMovePath(path string) *ImageContext {
+	return c.AddMovePathWithStyle(path, nil)
+}
+
+// AddMovePathWithStyle adds a move that passes through several squares, with a specific style.
+// See AddMovePath.
+func (c *ImageContext) AddMovePathWithStyle(path string, style *MoveStyle) *ImageContext {
+	squares := strings.Split(path, "-")
+	move := Move{From: squares[0], To: squares[len(squares)-1], Style: style}
+	if len(squares) > 2 {
+		move.Via = squares[1 : len(squares)-1]
+	}
+	c.Moves = append(c.Moves, move)
+
+	return c
+}
+
+// ShowAttackHeatmap paints a heatmap that shows, for each square, how many white pieces
+// attack it minus how many black pieces attack it. Squares that are not attacked are not painted.
+func (c *ImageContext) ShowAttackHeatmap() *ImageContext {
+	return c.ShowAttackHeatmapWithStyle(nil)
+}
+
+// ShowAttackHeatmapWithStyle paints an attack heatmap with a specific style. See ShowAttackHeatmap.
+func (c *ImageContext) ShowAttackHeatmapWithStyle(style *HeatmapStyle) *ImageContext {
+	c.AttackHeatmap = &AttackHeatmap{Style: style}
+
+	return c
+}
+
+// SetSquareValues paints a heatmap of arbitrary values, like a piece-square table, move
+// frequencies or the time spent on each square. The values are keyed by square, like "e4".
+// Squares without a value are not painted. The values must be finite numbers.
+func (c *ImageContext) SetSquareValues(values map[string]float64) *ImageContext {
+	return c.SetSquareValuesWithStyle(values, nil)
+}
+
+// SetSquareValuesWithStyle paints a heatmap of arbitrary values with a specific style.
+// See SetSquareValues.
+func (c *ImageContext) SetSquareValuesWithStyle(values map[string]float64, style *HeatmapStyle) *ImageContext {
+	c.SquareValues = &SquareValues{Values: values, Style: style}
+
+	return c
+}
+
+// ShowTactics shows tactical hints for both sides, that are computed from the FEN string: pieces
+// that are attacked and not defended, absolute pins, discovered attacks and forks. Which hints are
+// shown, and how, is set in the tactics style. The hints are rendered as highlighted squares and moves.
+func (c *ImageContext) ShowTactics() *ImageContext {
+	return c.ShowTacticsWithStyle(nil)
+}
+
+// ShowTacticsWithStyle shows tactical hints with a specific style. See ShowTactics.
+func (c *ImageContext) ShowTacticsWithStyle(style *TacticsStyle) *ImageContext {
+	c.TacticalHints = &TacticalHints{Style: style}
+
+	return c
+}
+
+// ShowLegalMoves shows the legal moves of the piece on a square, like "g1", the way online
+// boards do: the square of the piece is highlighted, and there is a dot on each empty square that
+// the piece can move to, and a ring around each piece that it can capture. The moves are computed
+// from the FEN string, including pins, check, castling and en passant.
+func (c *ImageContext) ShowLegalMoves(square string) *ImageContext {
+	return c.ShowLegalMovesWithStyle(square, nil)
+}
+
+// ShowLegalMovesWithStyle shows the legal moves of a piece with a specific style. See ShowLegalMoves.
+func (c *ImageContext) ShowLegalMovesWithStyle(square string, style *LegalMovesStyle) *ImageContext {
+	c.LegalMoves = &LegalMoves{Square: square, Style: style}
+
+	return c
+}
+
+// AddLastMoveFromPositions highlights the last move, when there is no move list, only the FEN
+// string of the previous position. The move is inferred by comparing the previous position with
+// the position of the context, and can be a castling move, an en passant capture or a promotion.
+// The squares that the piece moved from and to are highlighted, and an arrow is drawn if the
+// arrow of the last move style is turned on. An error is returned when rendering, if the positions
+// do not differ by exactly one legal move.
+func (c *ImageContext) AddLastMoveFromPositions(prevFEN string) *ImageContext {
+	return c.AddLastMoveFromPositionsWithStyle(prevFEN, nil)
+}
+
+// AddLastMoveFromPositionsWithStyle highlights the last move with a specific style. See AddLastMoveFromPositions.
+func (c *ImageContext) AddLastMoveFromPositionsWithStyle(prevFEN string, style *LastMoveStyle) *ImageContext {
+	c.LastMove = &LastMove{PreviousFen: prevFEN, Style: style}
+
+	return c
+}
+
+// AddSettingsOverlay adds a partial JSON settings document, that is deep merged into the
+// imager settings when this context is rendered. The imager itself is not changed.
+// See Imager.ApplySettings for the merge rules.
+func (c *ImageContext) AddSettingsOverlay(overlay string) *ImageContext {
+	c.SettingsOverlays = append(c.SettingsOverlays, overlay)
+
+	return c
+}
+
+// NewHighlightStyle creates a new highlight style. All colors in the New*Style functions
+// can use any format supported by the settings files, like "#F00", "red", "rgb(255, 0, 0)",
+// "hsl(0, 100%, 50%)" or palette references like "$accent" (only for contexts that were
+// created by Imager.NewContext).
 func (c *ImageContext) NewHighlightStyle(typ HighlightType, color string, width int, factor float64) (*HighlightStyle, error) {
-	col, err := hexToRGBA(color)
+	col, err := c.parseColor(color)
 	if err != nil {
 		return nil, err
 	}
 	return &HighlightStyle{
 		Type:   typ,
-		Color:  ColorRGBA{col},
+		Color:  ColorRGBA{RGBA: col},
 		Width:  width,
 		Factor: factor,
 	}, nil
@@ -80,17 +245,17 @@ func (c *ImageContext) NewHighlightStyle(typ HighlightType, color string, width
 func (c *ImageContext) NewAnnotationStyle(pos PositionType, size, fontSize, borderWidth int, bgc, fc,
 	bc string) (*AnnotationStyle, error) {
 
-	fCol, err := hexToRGBA(fc)
+	fCol, err := c.parseColor(fc)
 	if err != nil {
 		return nil, err
 	}
 
-	bgCol, err := hexToRGBA(bgc)
+	bgCol, err := c.parseColor(bgc)
 	if err != nil {
 		return nil, err
 	}
 
-	bCol, err := hexToRGBA(bc)
+	bCol, err := c.parseColor(bc)
 	if err != nil {
 		return nil, err
 	}
@@ -98,10 +263,10 @@ func (c *ImageContext) NewAnnotationStyle(pos PositionType, size, fontSize, bord
 	return &AnnotationStyle{
 		Position:        pos,
 		Size:            size,
-		FontColor:       ColorRGBA{fCol},
+		FontColor:       ColorRGBA{RGBA: fCol},
 		FontSize:        fontSize,
-		BackgroundColor: ColorRGBA{bgCol},
-		BorderColor:     ColorRGBA{bCol},
+		BackgroundColor: ColorRGBA{RGBA: bgCol},
+		BorderColor:     ColorRGBA{RGBA: bCol},
 		BorderWidth:     borderWidth,
 	}, nil
 }
@@ -109,21 +274,56 @@ func (c *ImageContext) NewAnnotationStyle(pos PositionType, size, fontSize, bord
 // NewMoveStyle creates a new move style.
 func (c *ImageContext) NewMoveStyle(typ MoveType, color string, color2 string, factor float64,
 	padding float64) (*MoveStyle, error) {
-	col, err := hexToRGBA(color)
+	col, err := c.parseColor(color)
 	if err != nil {
 		return nil, err
 	}
 
-	col2, err := hexToRGBA(color2)
+	col2, err := c.parseColor(color2)
 	if err != nil {
 		return nil, err
 	}
 
 	return &MoveStyle{
-		Color:   ColorRGBA{col},
-		Color2:  ColorRGBA{col2},
+		Color:   ColorRGBA{RGBA: col},
+		Color2:  ColorRGBA{RGBA: col2},
 		Type:    typ,
 		Factor:  factor,
 		Padding: padding,
 	}, nil
 }
+
+// NewPieceStyle creates a new piece style. Use an empty tint string, for no tint.
+func (c *ImageContext) NewPieceStyle(opacity float64, tint string, grayscale bool) (*PieceStyle, error) {
+	var col color.RGBA
+	if tint != "" {
+		var err error
+		col, err = c.parseColor(tint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &PieceStyle{
+		Opacity:   opacity,
+		Tint:      ColorRGBA{RGBA: col},
+		Grayscale: grayscale,
+	}, nil
+}
+
+// NewHeatmapStyle creates a new heatmap style, using one of the named colormaps, like "diverging",
+// "magma" or "viridis". The legend and label font settings, and the label format, of the default
+// heatmap style are used, unless they are set.
+func (c *ImageContext) NewHeatmapStyle(colormap string, opacity float64, legend bool) (*HeatmapStyle, error) {
+	style := &HeatmapStyle{Colormap: colormap, Opacity: opacity, Legend: legend}
+	if _, err := style.getColormap(); err != nil {
+		return nil, err
+	}
+
+	return style, nil
+}
+
+// parseColor converts a color string to a color, using the palette of the context.
+func (c *ImageContext) parseColor(s string) (color.RGBA, error) {
+	return parseColor(s, c.palette)
+}
@@ -0,0 +1,198 @@
+package chessImager
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// applyPieceEffects returns a copy of the piece image with the effects drawn below
+// the piece. The image is padded equally on all sides, so that the effects fit and
+// the piece stays centered. All effect sizes are multiplied by scale.
+func applyPieceEffects(img image.Image, e *PieceEffects, scale float64) image.Image {
+	if e == nil || (e.Shadow == nil && e.Outline == nil && e.Glow == nil) {
+		return img
+	}
+
+	pad := e.padding(scale)
+	size := img.Bounds().Size()
+	pieceRect := image.Rect(pad, pad, pad+size.X, pad+size.Y)
+	dst := image.NewRGBA(image.Rect(0, 0, size.X+2*pad, size.Y+2*pad))
+
+	// The alpha channel of the piece, that the effects are created from
+	mask := image.NewAlpha(dst.Bounds())
+	draw.Draw(mask, pieceRect, img, img.Bounds().Min, draw.Src)
+
+	if s := e.Shadow; s != nil {
+		shadow := blurAlpha(mask, s.Blur*scale)
+		offset := image.Pt(int(math.Round(s.OffsetX*scale)), int(math.Round(s.OffsetY*scale)))
+		r := dst.Bounds().Add(offset).Intersect(dst.Bounds())
+		draw.DrawMask(dst, r, image.NewUniform(toNRGBA(s.Color.RGBA)), image.Point{}, shadow, r.Min.Sub(offset), draw.Over)
+	}
+
+	if g := e.Glow; g != nil {
+		glow := blurAlpha(mask, g.Radius*scale)
+		// Strengthen the glow, since blurring halves the alpha at the edge of the piece
+		for n, a := range glow.Pix {
+			glow.Pix[n] = uint8(math.Min(255, float64(a)*2))
+		}
+		draw.DrawMask(dst, dst.Bounds(), image.NewUniform(toNRGBA(g.Color.RGBA)), image.Point{}, glow, image.Point{}, draw.Over)
+	}
+
+	if o := e.Outline; o != nil {
+		outline := dilateAlpha(mask, o.Width*scale)
+		draw.DrawMask(dst, dst.Bounds(), image.NewUniform(toNRGBA(o.Color.RGBA)), image.Point{}, outline, image.Point{}, draw.Over)
+	}
+
+	draw.Draw(dst, pieceRect, img, img.Bounds().Min, draw.Over)
+
+	return dst
+}
+
+// padding returns the number of pixels that is needed on each side of a piece, to fit the effects.
+func (e *PieceEffects) padding(scale float64) int {
+	var pad float64
+	if s := e.Shadow; s != nil {
+		pad = math.Max(pad, math.Max(math.Abs(s.OffsetX), math.Abs(s.OffsetY))+s.Blur)
+	}
+	if o := e.Outline; o != nil {
+		pad = math.Max(pad, o.Width)
+	}
+	if g := e.Glow; g != nil {
+		pad = math.Max(pad, g.Radius)
+	}
+
+	return int(math.Ceil(pad*scale)) + 1
+}
+
+// dilateAlpha grows the mask by radius pixels in every direction.
+func dilateAlpha(mask *image.Alpha, radius float64) *image.Alpha {
+	if radius <= 0 {
+		return mask
+	}
+
+	// The offsets of all the pixels within the radius
+	var offsets []image.Point
+	r := int(math.Ceil(radius))
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if math.Hypot(float64(dx), float64(dy)) <= radius {
+				offsets = append(offsets, image.Pt(dx, dy))
+			}
+		}
+	}
+
+	b := mask.Bounds()
+	dst := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var max uint8
+			for _, o := range offsets {
+				p := image.Pt(x+o.X, y+o.Y)
+				if p.In(b) {
+					if a := mask.AlphaAt(p.X, p.Y).A; a > max {
+						max = a
+					}
+				}
+			}
+			dst.SetAlpha(x, y, color.Alpha{A: max})
+		}
+	}
+
+	return dst
+}
+
+// blurAlpha blurs the mask with a gaussian blur, where radius is the blur radius in pixels.
+func blurAlpha(mask *image.Alpha, radius float64) *image.Alpha {
+	if radius <= 0 {
+		return mask
+	}
+
+	// The radius covers three standard deviations of the gaussian
+	sigma := radius / 3
+	r := int(math.Ceil(radius))
+	kernel := make([]float64, 2*r+1)
+	var sum float64
+	for n := range kernel {
+		d := float64(n - r)
+		kernel[n] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[n]
+	}
+	for n := range kernel {
+		kernel[n] /= sum
+	}
+
+	b := mask.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src[y*w+x] = float64(mask.AlphaAt(b.Min.X+x, b.Min.Y+y).A)
+		}
+	}
+
+	// The gaussian blur is separable, so blur horizontally and then vertically
+	tmp := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v float64
+			for n, k := range kernel {
+				if xx := x + n - r; xx >= 0 && xx < w {
+					v += src[y*w+xx] * k
+				}
+			}
+			tmp[y*w+x] = v
+		}
+	}
+
+	dst := image.NewAlpha(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v float64
+			for n, k := range kernel {
+				if yy := y + n - r; yy >= 0 && yy < h {
+					v += tmp[yy*w+x] * k
+				}
+			}
+			dst.SetAlpha(b.Min.X+x, b.Min.Y+y, color.Alpha{A: uint8(math.Min(255, math.Round(v)))})
+		}
+	}
+
+	return dst
+}
+
+// applyPieceStyle returns a copy of the piece image, where the style has been applied.
+func applyPieceStyle(img image.Image, style *PieceStyle) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	tint := float64(style.Tint.A) / 255
+	opacity := math.Max(0, math.Min(1, style.Opacity))
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			// Premultiplied colors, 0-1
+			cr, cg, cb, ca := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			r, g, bl, a := float64(cr)/0xffff, float64(cg)/0xffff, float64(cb)/0xffff, float64(ca)/0xffff
+
+			if style.Grayscale {
+				l := 0.299*r + 0.587*g + 0.114*bl
+				r, g, bl = l, l, l
+			}
+			if tint > 0 {
+				r = lerp(r, float64(style.Tint.R)/255*a, tint)
+				g = lerp(g, float64(style.Tint.G)/255*a, tint)
+				bl = lerp(bl, float64(style.Tint.B)/255*a, tint)
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(math.Round(r * opacity * 255)),
+				G: uint8(math.Round(g * opacity * 255)),
+				B: uint8(math.Round(bl * opacity * 255)),
+				A: uint8(math.Round(a * opacity * 255)),
+			})
+		}
+	}
+
+	return dst
+}
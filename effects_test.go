@@ -0,0 +1,65 @@
+package chessImager
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPieceEffects(t *testing.T) {
+	t.Parallel()
+
+	imager, err := NewImagerFromPath("test/data/pieceEffects.json")
+	if err != nil {
+		t.Fatalf("Failed to load JSON file: %v", err)
+	}
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+
+	compareImages(t, "pieceEffects.png", &img)
+}
+
+func TestApplyPieceEffects(t *testing.T) {
+	t.Parallel()
+
+	// A 10x10 opaque square
+	piece := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for n := range piece.Pix {
+		piece.Pix[n] = 255
+	}
+
+	if got := applyPieceEffects(piece, &PieceEffects{}, 1); got != image.Image(piece) {
+		t.Errorf("applyPieceEffects() without effects should return the image itself")
+	}
+
+	red := ColorRGBA{RGBA: color.RGBA{R: 255, A: 255}}
+	effects := &PieceEffects{
+		Shadow:  &PieceShadow{OffsetX: 4, OffsetY: -2, Color: red},
+		Outline: &PieceOutline{Width: 2, Color: red},
+	}
+	got := applyPieceEffects(piece, effects, 2)
+
+	// Shadow offset (8 pixels) + 1 pixel on each side
+	if size := got.Bounds().Size(); size != image.Pt(28, 28) {
+		t.Fatalf("applyPieceEffects() size = %v, want (28,28)", size)
+	}
+	tests := []struct {
+		x, y int
+		want color.Color
+	}{
+		{14, 14, color.RGBA{R: 255, G: 255, B: 255, A: 255}}, // The piece
+		{7, 14, color.RGBA{R: 255, A: 255}},                  // The outline (4 pixels wide)
+		{4, 14, color.RGBA{}},                                // Outside the outline
+		{24, 6, color.RGBA{R: 255, A: 255}},                  // The shadow
+		{24, 24, color.RGBA{}},                               // Outside the shadow
+	}
+	for _, tt := range tests {
+		if c := got.At(tt.x, tt.y); c != tt.want {
+			t.Errorf("applyPieceEffects() color at (%d,%d) = %v, want %v", tt.x, tt.y, c, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package chessImager
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// EncodeOptions contains format specific options for encoding images.
+// PNGCompression : The PNG compression level, default is png.DefaultCompression
+// JPEGQuality : The JPEG quality (1-100), 0 means jpeg.DefaultQuality
+// GIFPalette : The GIF palette (max 256 colors), nil means palette.Plan9
+// GIFDither : Use Floyd-Steinberg dithering when converting to the GIF palette
+type EncodeOptions struct {
+	PNGCompression png.CompressionLevel
+	JPEGQuality    int
+	GIFPalette     color.Palette
+	GIFDither      bool
+}
+
+var extension2Format = map[string]Format{
+	".png":  FormatPNG,
+	".jpg":  FormatJPEG,
+	".jpeg": FormatJPEG,
+	".gif":  FormatGIF,
+	".webp": FormatWebP,
+}
+
+// FormatFromFilename returns the image format, based on the extension of a filename.
+func FormatFromFilename(filename string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	f, ok := extension2Format[ext]
+	if !ok {
+		return 0, fmt.Errorf("unknown image format extension : %q", ext)
+	}
+
+	return f, nil
+}
+
+// Encode renders an image of a chess board based on an image context,
+// and encodes it to w in the given format.
+func (i *Imager) Encode(w io.Writer, ctx *ImageContext, format Format, opts EncodeOptions) error {
+	img, err := i.RenderWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return EncodeImage(w, img, format, opts)
+}
+
+// EncodeInverted renders an image of an inverted chess board based on an image context,
+// and encodes it to w in the given format.
+func (i *Imager) EncodeInverted(w io.Writer, ctx *ImageContext, format Format, opts EncodeOptions) error {
+	img, err := i.RenderWithContextInverted(ctx)
+	if err != nil {
+		return err
+	}
+
+	return EncodeImage(w, img, format, opts)
+}
+
+// EncodeImage encodes an already rendered image to w in the given format.
+func EncodeImage(w io.Writer, img image.Image, format Format, opts EncodeOptions) error {
+	switch format {
+	case FormatPNG:
+		e := png.Encoder{CompressionLevel: opts.PNGCompression}
+		return e.Encode(w, img)
+	case FormatJPEG:
+		quality := opts.JPEGQuality
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+		if quality < 1 || quality > 100 {
+			return fmt.Errorf("invalid jpeg quality : %d", quality)
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case FormatGIF:
+		p := opts.GIFPalette
+		if p == nil {
+			p = palette.Plan9
+		}
+		if len(p) < 1 || len(p) > 256 {
+			return fmt.Errorf("invalid gif palette size : %d", len(p))
+		}
+		var drawer draw.Drawer = draw.Src
+		if opts.GIFDither {
+			drawer = draw.FloydSteinberg
+		}
+		paletted := image.NewPaletted(img.Bounds(), p)
+		drawer.Draw(paletted, img.Bounds(), img, img.Bounds().Min)
+		return gif.Encode(w, paletted, &gif.Options{NumColors: len(p)})
+	case FormatWebP:
+		return encodeWebP(w, img)
+	default:
+		return fmt.Errorf("invalid image format : %v", format)
+	}
+}
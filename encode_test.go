@@ -0,0 +1,137 @@
+package chessImager
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestFormatFromFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filename string
+		want     Format
+		wantErr  bool
+	}{
+		{"board.png", FormatPNG, false},
+		{"/tmp/board.PNG", FormatPNG, false},
+		{"board.jpg", FormatJPEG, false},
+		{"board.jpeg", FormatJPEG, false},
+		{"board.gif", FormatGIF, false},
+		{"board.webp", FormatWebP, false},
+		{"board.bmp", 0, true},
+		{"board", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := FormatFromFilename(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatFromFilename() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FormatFromFilename() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	imager := NewImager()
+
+	decoders := map[Format]func(*bytes.Reader) (image.Image, error){
+		FormatPNG:  func(r *bytes.Reader) (image.Image, error) { return png.Decode(r) },
+		FormatJPEG: func(r *bytes.Reader) (image.Image, error) { return jpeg.Decode(r) },
+		FormatGIF:  func(r *bytes.Reader) (image.Image, error) { return gif.Decode(r) },
+		FormatWebP: func(r *bytes.Reader) (image.Image, error) { return webp.Decode(r) },
+	}
+
+	for format, decode := range decoders {
+		var buf bytes.Buffer
+		ctx := imager.NewContext(fen).AddMove("e1", "e7")
+		err := imager.Encode(&buf, ctx, format, EncodeOptions{JPEGQuality: 80})
+		if err != nil {
+			t.Fatalf("failed to encode format %v : %v", format, err)
+		}
+
+		img, err := decode(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("failed to decode format %v : %v", format, err)
+		}
+		if got := img.Bounds().Size(); got != image.Pt(648, 648) {
+			t.Errorf("format %v : got size = %v, want 648x648", format, got)
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	var buf bytes.Buffer
+
+	if err := EncodeImage(&buf, img, Format(99), EncodeOptions{}); err == nil {
+		t.Errorf("invalid format returned no error")
+	}
+	if err := EncodeImage(&buf, img, FormatJPEG, EncodeOptions{JPEGQuality: 101}); err == nil {
+		t.Errorf("invalid jpeg quality returned no error")
+	}
+	if err := EncodeImage(&buf, img, FormatGIF, EncodeOptions{GIFPalette: color.Palette{}}); err == nil {
+		t.Errorf("empty gif palette returned no error")
+	}
+}
+
+func TestEncodeWebPLossless(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := NewImager().Render("b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25")
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	// An image with random colors and transparency
+	noise := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	rnd := rand.New(rand.NewSource(1))
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(rnd.Intn(256))
+	}
+
+	// A single colored image
+	flat := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	for i := range flat.Pix {
+		flat.Pix[i] = 0x80
+	}
+
+	for name, img := range map[string]image.Image{"rendered": rendered, "noise": noise, "flat": flat} {
+		var buf bytes.Buffer
+		if err := EncodeImage(&buf, img, FormatWebP, EncodeOptions{}); err != nil {
+			t.Fatalf("%s : failed to encode : %v", name, err)
+		}
+		decoded, err := webp.Decode(&buf)
+		if err != nil {
+			t.Fatalf("%s : failed to decode : %v", name, err)
+		}
+
+		b := img.Bounds()
+		for y := 0; y < b.Dy(); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				want := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y))
+				got := color.NRGBAModel.Convert(decoded.At(x, y))
+				if got != want {
+					t.Fatalf("%s : pixel (%d,%d) got = %v, want %v", name, x, y, got, want)
+				}
+			}
+		}
+	}
+}
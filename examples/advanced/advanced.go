@@ -1,7 +1,6 @@
 package main
 
 import (
-	"image/png"
 	"os"
 
 	"github.com/Hultan/chessImager"
@@ -45,14 +44,11 @@ func main() {
 	// show move e1-e7.
 	ctx.AddHighlightWithStyle("e7", hs).AddAnnotationWithStyle("e7", "!!", as).AddMoveWithStyle("e1", "e7", ms)
 
-	// Render the image
-	img, err := imager.RenderWithContext(ctx)
+	// Render the image, and save it as a PNG file
+	file, _ := os.Create("examples/advanced/advanced.png")
+	defer file.Close()
+	err := imager.Encode(file, ctx, chessImager.FormatPNG, chessImager.EncodeOptions{})
 	if err != nil {
 		panic(err)
 	}
-
-	// Save the image
-	file, _ := os.Create("examples/advanced/advanced.png")
-	defer file.Close()
-	_ = png.Encode(file, img)
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"image/png"
 	"os"
 
 	"github.com/Hultan/chessImager"
@@ -15,11 +14,8 @@ func main() {
 	// and black queen side castling.
 	ctx := imager.NewContext("2kr4/8/8/8/8/8/8/5RK1 b - - 1 25").AddMove("0-0", "").AddMove("", "0-0-0")
 
-	// Render the image
-	img, _ := imager.RenderWithContext(ctx)
-
-	// Save the image to a file
+	// Render the image, and save it as a PNG file
 	file, _ := os.Create("examples/castling/castling.png")
 	defer file.Close()
-	_ = png.Encode(file, img)
+	_ = imager.Encode(file, ctx, chessImager.FormatPNG, chessImager.EncodeOptions{})
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"image/png"
 	"os"
 
 	"github.com/Hultan/chessImager"
@@ -19,11 +18,8 @@ func main() {
 	// Show move e1-e7
 	ctx.AddHighlight("e7").AddAnnotation("e7", "!!").AddMove("e1", "e7")
 
-	// Render the image
-	img, _ := imager.RenderWithContext(ctx)
-
-	// Save the image to a file
+	// Render the image, and save it as a PNG file
 	file, _ := os.Create("examples/medium/medium.png")
 	defer file.Close()
-	_ = png.Encode(file, img)
+	_ = imager.Encode(file, ctx, chessImager.FormatPNG, chessImager.EncodeOptions{})
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"image/png"
 	"os"
 
 	"github.com/Hultan/chessImager"
@@ -18,11 +17,8 @@ func main() {
 		AddAnnotation("e7", "!!").
 		AddMove("e1", "e7")
 
-	// Render the image
-	img, _ := imager.RenderWithContext(ctx)
-
-	// Save the image
+	// Render the image, and save it as a PNG file
 	file, _ := os.Create("examples/other/other.png")
 	defer file.Close()
-	_ = png.Encode(file, img)
+	_ = imager.Encode(file, ctx, chessImager.FormatPNG, chessImager.EncodeOptions{})
 }
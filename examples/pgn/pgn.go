@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"image/png"
 	"log"
 	"os"
 
@@ -35,10 +34,11 @@ func main() {
 				AddHighlight(move.From.String()).
 				AddHighlight(move.To.String())
 
-			img, _ := imager.RenderWithContext(ctx)
+			filename := fmt.Sprintf("%d.png", i)
+			format, _ := chessImager.FormatFromFilename(filename)
 
-			file, _ := os.Create(fmt.Sprintf("%d.png", i))
-			_ = png.Encode(file, img)
+			file, _ := os.Create(filename)
+			_ = imager.Encode(file, ctx, format, chessImager.EncodeOptions{})
 			_ = file.Close()
 			i++
 		}
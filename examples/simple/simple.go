@@ -1,19 +1,17 @@
 package main
 
 import (
-	"image/png"
 	"os"
 
 	"github.com/Hultan/chessImager"
 )
 
 func main() {
-	// Render simple image
+	// Render simple image, and save it as a PNG file
 	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
-	img, _ := chessImager.NewImager().Render(fen)
+	imager := chessImager.NewImager()
 
-	// Save image
 	file, _ := os.Create("examples/simple/simple.png")
 	defer file.Close()
-	_ = png.Encode(file, img)
+	_ = imager.Encode(file, imager.NewContext(fen), chessImager.FormatPNG, chessImager.EncodeOptions{})
 }
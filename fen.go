@@ -95,3 +95,32 @@ func normalizeFENRank(fenRank string) string {
 	}
 	return normalized
 }
+
+// compressFEN is the inverse of normalizeFEN, it converts a normalized
+// board (ranks separated by "/" and empty squares as spaces) back into
+// the board section of a FEN string.
+func compressFEN(normalized string) string {
+	var sb strings.Builder
+	for i, rank := range strings.Split(normalized, "/") {
+		if i > 0 {
+			sb.WriteString("/")
+		}
+		empty := 0
+		for _, symbol := range rank {
+			if symbol == ' ' {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			sb.WriteRune(symbol)
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,198 @@
+package chessImager
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/fogleman/gg"
+	"github.com/nfnt/resize"
+)
+
+// defaultNoiseSize is the size of the noise features in pixels, when Fill.Size is not set.
+const defaultNoiseSize = 16
+
+// fillRectangles paints the rectangles using a fill. Gradients and noise cover
+// the area, so that the rectangles look like parts of one larger surface.
+// Textures are tiled, and shifted randomly for each rectangle if Fill.RandomOffset
+// is set.
+func (i *Imager) fillRectangles(dc *gg.Context, fill *Fill, area Rectangle, rects []Rectangle) error {
+	var pattern gg.Pattern
+	var texture image.Image
+	var rnd *rand.Rand
+
+	switch fill.Type {
+	case fillTypeLinearGradient, fillTypeRadialGradient:
+		if len(fill.Colors) < 2 {
+			return fmt.Errorf("invalid fill : a gradient needs at least 2 colors, got %d", len(fill.Colors))
+		}
+		pattern = newGradient(fill, area)
+	case fillTypeTexture:
+		img, err := i.loadTexture(fill.Path)
+		if err != nil {
+			return err
+		}
+		texture = img
+		rnd = rand.New(rand.NewSource(fill.Seed))
+		pattern = &texturePattern{img: texture}
+	case fillTypeNoise:
+		if len(fill.Colors) != 2 {
+			return fmt.Errorf("invalid fill : noise needs 2 colors, got %d", len(fill.Colors))
+		}
+		size := fill.Size
+		if size == 0 {
+			size = defaultNoiseSize
+		}
+		pattern = &noisePattern{
+			from: fill.Colors[0].RGBA,
+			to:   fill.Colors[1].RGBA,
+			size: i.scaled(size),
+			seed: fill.Seed,
+		}
+	default:
+		return fmt.Errorf("invalid fill type : %v", fill.Type)
+	}
+
+	for _, rect := range rects {
+		if texture != nil && fill.RandomOffset {
+			size := texture.Bounds().Size()
+			pattern = &texturePattern{img: texture, dx: rnd.Intn(size.X), dy: rnd.Intn(size.Y)}
+		}
+		dc.SetFillStyle(pattern)
+		dc.DrawRectangle(rect.coords())
+		dc.Fill()
+	}
+
+	return nil
+}
+
+// loadTexture loads a texture image, and resizes it according to the current scale factor.
+func (i *Imager) loadTexture(path string) (image.Image, error) {
+	f, err := i.openAsset(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load texture : %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode texture : %v", err)
+	}
+
+	if i.getScale() != 1 {
+		size := img.Bounds().Size()
+		img = resize.Resize(uint(scaleInt(size.X, i.getScale())), uint(scaleInt(size.Y, i.getScale())),
+			img, resize.Lanczos3)
+	}
+
+	return img, nil
+}
+
+// newGradient creates a linear or radial gradient that covers the area.
+func newGradient(fill *Fill, area Rectangle) gg.Gradient {
+	cx, cy := area.X+area.Width/2, area.Y+area.Height/2
+
+	var gradient gg.Gradient
+	if fill.Type == fillTypeRadialGradient {
+		radius := math.Hypot(area.Width, area.Height) / 2
+		gradient = gg.NewRadialGradient(cx, cy, 0, cx, cy, radius)
+	} else {
+		angle := gg.Radians(fill.Angle)
+		dx, dy := math.Cos(angle), math.Sin(angle)
+		// Half the length of the gradient line, so that it reaches the corners of the area
+		l := math.Abs(area.Width/2*dx) + math.Abs(area.Height/2*dy)
+		gradient = gg.NewLinearGradient(cx-dx*l, cy-dy*l, cx+dx*l, cy+dy*l)
+	}
+
+	for n, col := range fill.Colors {
+		offset := 0.0
+		if len(fill.Colors) > 1 {
+			offset = float64(n) / float64(len(fill.Colors)-1)
+		}
+		gradient.AddColorStop(offset, toNRGBA(col.RGBA))
+	}
+
+	return gradient
+}
+
+// texturePattern tiles an image, shifted by dx and dy pixels.
+type texturePattern struct {
+	img    image.Image
+	dx, dy int
+}
+
+func (p *texturePattern) ColorAt(x, y int) color.Color {
+	b := p.img.Bounds()
+	return p.img.At(b.Min.X+mod(x+p.dx, b.Dx()), b.Min.Y+mod(y+p.dy, b.Dy()))
+}
+
+// noisePattern is a smooth value noise, that blends between two colors.
+type noisePattern struct {
+	from, to color.RGBA
+	size     float64
+	seed     int64
+}
+
+func (p *noisePattern) ColorAt(x, y int) color.Color {
+	// Three octaves of value noise, for a more natural look
+	var t, total float64
+	amplitude, frequency := 1.0, 1.0
+	for octave := 0; octave < 3; octave++ {
+		t += amplitude * p.noise(float64(x)*frequency/p.size, float64(y)*frequency/p.size, int64(octave))
+		total += amplitude
+		amplitude /= 2
+		frequency *= 2
+	}
+	t /= total
+
+	return color.NRGBA{
+		R: lerpUint8(p.from.R, p.to.R, t),
+		G: lerpUint8(p.from.G, p.to.G, t),
+		B: lerpUint8(p.from.B, p.to.B, t),
+		A: lerpUint8(p.from.A, p.to.A, t),
+	}
+}
+
+// noise returns the smoothly interpolated value noise (0-1) at x, y.
+func (p *noisePattern) noise(x, y float64, octave int64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	ix, iy := int64(x0), int64(y0)
+	sx, sy := smoothstep(x-x0), smoothstep(y-y0)
+
+	top := lerp(p.lattice(ix, iy, octave), p.lattice(ix+1, iy, octave), sx)
+	bottom := lerp(p.lattice(ix, iy+1, octave), p.lattice(ix+1, iy+1, octave), sx)
+
+	return lerp(top, bottom, sy)
+}
+
+// lattice returns a pseudo random value (0-1) for a lattice point.
+func (p *noisePattern) lattice(x, y, octave int64) float64 {
+	h := uint64(x)*0x9E3779B97F4A7C15 ^ uint64(y)*0xC2B2AE3D27D4EB4F ^ uint64(p.seed+octave)*0x165667B19E3779F9
+	h ^= h >> 29
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 32
+
+	return float64(h>>11) / float64(1<<53)
+}
+
+func toNRGBA(c color.RGBA) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+func smoothstep(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func lerpUint8(a, b uint8, t float64) uint8 {
+	return uint8(math.Round(lerp(float64(a), float64(b), t)))
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
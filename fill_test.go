@@ -0,0 +1,82 @@
+package chessImager
+
+import (
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestFillGradient(t *testing.T) {
+	t.Parallel()
+
+	imager, err := NewImagerFromPath("test/data/fillGradient.json")
+	if err != nil {
+		t.Fatalf("Failed to load JSON file: %v", err)
+	}
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+
+	compareImages(t, "fillGradient.png", &img)
+}
+
+func TestFillTextureAndNoise(t *testing.T) {
+	t.Parallel()
+
+	imager, err := NewImagerFromPath("test/data/fillTexture.json")
+	if err != nil {
+		t.Fatalf("Failed to load JSON file: %v", err)
+	}
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+
+	compareImages(t, "fillTexture.png", &img)
+}
+
+func TestFillInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		overlay string
+		problem string
+	}{
+		{`{"board": {"default": {"white_fill": {"type": 0, "colors": ["#FFF"]}}}}`, "board.default.white_fill.colors"},
+		{`{"board": {"default": {"black_fill": {"type": 2, "path": "missing.png"}}}}`, "board.default.black_fill.path"},
+		{`{"border": {"fill": {"type": 3, "colors": ["#FFF", "#000", "#F00"]}}}`, "border.fill.colors"},
+		{`{"border": {"fill": {"type": 9}}}`, "border.fill.type"},
+	}
+	for _, tt := range tests {
+		err := NewImager().ApplySettings(strings.NewReader(tt.overlay))
+		var verr *ValidationError
+		if !errors.As(err, &verr) || !strings.Contains(verr.Error(), tt.problem) {
+			t.Errorf("ApplySettings(%s) error = %v, want problem %v", tt.overlay, err, tt.problem)
+		}
+	}
+}
+
+func TestFillInvalidColors(t *testing.T) {
+	t.Parallel()
+
+	const fen = "8/8/8/8/8/8/8/8 w - - 0 1"
+	for _, fill := range []*Fill{
+		{Type: fillTypeLinearGradient, Colors: []ColorRGBA{{RGBA: color.RGBA{A: 255}}}},
+		{Type: fillTypeRadialGradient},
+		{Type: fillTypeNoise},
+		{Type: fillTypeNoise, Colors: make([]ColorRGBA, 3)},
+	} {
+		// The settings are changed directly, so they are not validated
+		imager := NewImager()
+		imager.settings.Border.Fill = fill
+		if _, err := imager.Render(fen); err == nil {
+			t.Errorf("Render() with fill %+v returned no error", fill)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package chessImager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// settingsToJSON converts a settings document to JSON, based on the extension of
+// its file name. Files ending with .yaml or .yml are decoded as YAML, and files
+// ending with .toml are decoded as TOML. All other files are assumed to be JSON.
+func settingsToJSON(name string, data []byte) ([]byte, error) {
+	var doc any
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid YAML settings file : %v", err)
+		}
+		doc = fixYAMLMaps(doc)
+	case ".toml":
+		m := map[string]any{}
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid TOML settings file : %v", err)
+		}
+		doc = m
+	default:
+		return data, nil
+	}
+
+	if doc == nil {
+		// An empty YAML document does not change any settings
+		doc = map[string]any{}
+	}
+
+	return json.Marshal(doc)
+}
+
+// fixYAMLMaps converts the maps that the YAML decoder produces for mappings with
+// non-string keys, to maps with string keys, so that they can be encoded as JSON.
+func fixYAMLMaps(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = fixYAMLMaps(value)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = fixYAMLMaps(value)
+		}
+		return m
+	case []any:
+		for n, value := range v {
+			v[n] = fixYAMLMaps(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
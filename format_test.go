@@ -0,0 +1,108 @@
+package chessImager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSettingsFormats(t *testing.T) {
+	t.Parallel()
+
+	want, err := NewImagerFromPath("test/data/rankAndFileSquare.json")
+	if err != nil {
+		t.Fatalf("Failed to load JSON file: %v", err)
+	}
+
+	for _, path := range []string{"test/data/rankAndFileSquare.yaml", "test/data/rankAndFileSquare.toml"} {
+		imager, err := NewImagerFromPath(path)
+		if err != nil {
+			t.Fatalf("Failed to load %v : %v", path, err)
+		}
+		if !reflect.DeepEqual(imager.settings, want.settings) {
+			t.Errorf("%v : got = %+v, want %+v", path, imager.settings, want.settings)
+		}
+
+		const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+		img, err := imager.Render(fen)
+		if err != nil {
+			t.Errorf("Failed to render chess board: %v", err)
+		}
+
+		compareImages(t, "rankAndFileSquare.png", &img)
+	}
+}
+
+func TestApplySettingsFromPathYAML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "overlay.yml")
+	err := os.WriteFile(path, []byte("border:\n  color: \"#123456\"\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed to write overlay : %v", err)
+	}
+
+	imager := NewImager()
+	if err = imager.ApplySettingsFromPath(path); err != nil {
+		t.Fatalf("ApplySettingsFromPath() failed : %v", err)
+	}
+	if got := imager.settings.Border.Color.RGBA; got != hexMust(t, "#123456") {
+		t.Errorf("ApplySettingsFromPath() border color = %v", got)
+	}
+}
+
+func TestSettingsFormatsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid.yaml", "border: [1, 2"},
+		{"invalid.yaml", "- 1\n- 2\n"},
+		{"invalid.yaml", "border:\n  colour: \"#123456\"\n"},
+		{"invalid.toml", "[border\nwidth = 1"},
+		{"invalid.toml", "[border]\nwidth = \"wide\""},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), tt.name)
+		if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+			t.Fatalf("failed to write settings : %v", err)
+		}
+		if _, err := NewImagerFromPath(path); err == nil {
+			t.Errorf("NewImagerFromPath(%q) returned no error", tt.data)
+		}
+	}
+}
+
+func TestSettingsSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SettingsSchema()
+	if err != nil {
+		t.Fatalf("SettingsSchema() failed : %v", err)
+	}
+
+	file, err := os.ReadFile("config/settings.schema.json")
+	if err != nil {
+		t.Fatalf("failed to read schema : %v", err)
+	}
+	if strings.TrimSpace(string(file)) != string(schema) {
+		t.Errorf("config/settings.schema.json is out of date, regenerate it with SettingsSchema()")
+	}
+}
+
+func TestSettingsSchemaReference(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(`{"$schema": "config/settings.schema.json", "border": {"width": 5}}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	if imager.settings.Border.Width != 5 {
+		t.Errorf("ApplySettings() border width = %v, want 5", imager.settings.Border.Width)
+	}
+}
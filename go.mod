@@ -3,12 +3,14 @@ module github.com/Hultan/chessImager
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/fogleman/gg v1.3.0
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/nfnt/resize v0.0.0-20180221191011-83c6a9932646
 	golang.org/x/exp v0.0.0-20240719175910-8a7402abbf56
 	golang.org/x/image v0.18.0
 	gopkg.in/freeeve/pgn.v1 v1.0.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
@@ -0,0 +1,95 @@
+package chessImager
+
+import (
+	"strings"
+	"testing"
+)
+
+const heatmapFen = "r1bqkb1r/pppp1ppp/2n2n2/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 0 1"
+
+func TestAttackHeatmap(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(heatmapFen).ShowAttackHeatmap()
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "attackHeatmap.png", &img)
+}
+
+func TestAttackHeatmapContested(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(heatmapFen)
+	style, err := ctx.NewHeatmapStyle("diverging", 0.7, true)
+	if err != nil {
+		t.Fatalf("NewHeatmapStyle() failed : %v", err)
+	}
+	style.ContestedOnly = true
+	ctx.ShowAttackHeatmapWithStyle(style)
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "attackHeatmapContested.png", &img)
+}
+
+func TestNewHeatmapStyleUnknown(t *testing.T) {
+	t.Parallel()
+
+	ctx := NewImager().NewContext(heatmapFen)
+	if _, err := ctx.NewHeatmapStyle("rainbow", 0.5, false); err == nil {
+		t.Errorf("NewHeatmapStyle() expected an error for an unknown colormap")
+	}
+}
+
+func TestHeatmapLegendNoBorder(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	if err := imager.ApplySettings(strings.NewReader(`{"border": {"width": 0}}`)); err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	ctx := imager.NewContext(heatmapFen)
+	style, err := ctx.NewHeatmapStyle("diverging", 0.7, true)
+	if err != nil {
+		t.Fatalf("NewHeatmapStyle() failed : %v", err)
+	}
+	ctx.ShowAttackHeatmapWithStyle(style)
+
+	if _, err = imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("RenderWithContext() expected an error for a legend without a border")
+	}
+}
+
+func Test_formatHeatmapValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings string
+		style    string
+		format   string
+		want     string
+	}{
+		{"default attack heatmap", "", "", "%+g", "+2"},
+		{"default square values", "", "", "%.4g", "2"},
+		{"settings format", "%.1f", "", "%+g", "2.0"},
+		{"style format", "%.1f", "%03.0f", "%+g", "002"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &rendererHighlight{Imager: NewImager()}
+			r.settings.HeatmapStyle.LabelFormat = tt.settings
+			got := r.formatHeatmapValue(&HeatmapStyle{LabelFormat: tt.style}, tt.format, 2)
+			if got != tt.want {
+				t.Errorf("formatHeatmapValue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package chessImager
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"math"
+	"strings"
+)
+
+// SquareAt returns the square (ex "e4") at the pixel position x, y in
+// a rendered image. The second return value is false if the position
+// is outside the chess board, or if the settings overlays of the context
+// are invalid. The settings overlays and the scale of the context are
+// applied, like when rendering. The context can be nil.
+// Inverted : Should be true if the image was rendered with black at the bottom.
+func (i *Imager) SquareAt(ctx *ImageContext, x, y int, inverted bool) (string, bool) {
+	c, err := i.forContext(ctx)
+	if err != nil {
+		return "", false
+	}
+	board := c.getBoardBox()
+	square := board.Width / 8
+
+	file := int(math.Floor((float64(x) - board.X) / square))
+	rank := invert(int(math.Floor((float64(y) - board.Y) / square)))
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return "", false
+	}
+
+	if inverted {
+		file, rank = invert(file), invert(rank)
+	}
+
+	return fmt.Sprintf("%c%c", 'a'+file, '1'+rank), true
+}
+
+// SquareRect returns the rectangle that the square (ex "e4") occupies in a
+// rendered image. An empty rectangle is returned if the square is invalid,
+// or if the settings overlays of the context are invalid. The settings
+// overlays and the scale of the context are applied, like when rendering.
+// The context can be nil.
+// Inverted : Should be true if the image was rendered with black at the bottom.
+func (i *Imager) SquareRect(ctx *ImageContext, square string, inverted bool) image.Rectangle {
+	c, err := i.forContext(ctx)
+	if err != nil {
+		return image.Rectangle{}
+	}
+
+	return c.squareRect(square, inverted)
+}
+
+// squareRect is SquareRect, for an imager that the context has already been applied to.
+func (i *Imager) squareRect(square string, inverted bool) image.Rectangle {
+	a, err := newAlg(square, inverted)
+	if err != nil || a.status != moveStatusNormal {
+		return image.Rectangle{}
+	}
+
+	return i.getSquareBox(a.coords()).toImageRect()
+}
+
+// forContext returns a copy of the imager, with the settings overlays and the scale
+// of the context applied, like when rendering. The context can be nil.
+func (i *Imager) forContext(ctx *ImageContext) (*Imager, error) {
+	c := *i
+	if ctx == nil {
+		return &c, nil
+	}
+
+	si, err := i.withOverlays(ctx.SettingsOverlays)
+	if err != nil {
+		return nil, err
+	}
+	si, err = si.withScale(ctx.Scale)
+	if err != nil {
+		return nil, err
+	}
+	c = *si
+
+	return &c, nil
+}
+
+// HTMLMap generates an HTML <map> element, with one <area> per annotation in
+// the context and one <area> per square, that can be used to make a rendered
+// image clickable. Annotations come first, so that they take precedence
+// over the square below them. The settings overlays and the scale of the
+// context are applied, like when rendering. The context can be nil.
+// Name : The name of the map, used in the usemap attribute of the <img> tag.
+// Inverted : Should be true if the image was rendered with black at the bottom.
+func (i *Imager) HTMLMap(ctx *ImageContext, name string, inverted bool) (string, error) {
+	// The geometry is computed on a copy, so that the imager is not changed
+	c, err := i.forContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.inverted = inverted
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<map name=\"%s\">\n", html.EscapeString(name)))
+
+	if ctx != nil {
+		r := &rendererAnnotation{Imager: c, ctx: ctx}
+		for _, annotation := range ctx.Annotations {
+			rect, err := r.getAnnotationRectangle(annotation)
+			if err != nil {
+				return "", err
+			}
+			x, y := rect.center()
+			square := html.EscapeString(strings.ToLower(annotation.Square))
+			text := html.EscapeString(annotation.Text)
+			sb.WriteString(fmt.Sprintf(
+				"  <area shape=\"circle\" coords=\"%d,%d,%d\" href=\"#%s\" alt=\"%s\" title=\"%s %s\" "+
+					"data-square=\"%s\" data-annotation=\"%s\">\n",
+				int(x), int(y), int(rect.Width/2), square, text, square, text, square, text))
+		}
+	}
+
+	for rank := 7; rank >= 0; rank-- {
+		for file := 0; file < 8; file++ {
+			square := fmt.Sprintf("%c%c", 'a'+file, '1'+rank)
+			rect := c.squareRect(square, inverted)
+			sb.WriteString(fmt.Sprintf(
+				"  <area shape=\"rect\" coords=\"%d,%d,%d,%d\" href=\"#%s\" alt=\"%s\" title=\"%s\" data-square=\"%s\">\n",
+				rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y, square, square, square, square))
+		}
+	}
+
+	sb.WriteString("</map>\n")
+
+	return sb.String(), nil
+}
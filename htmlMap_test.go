@@ -0,0 +1,156 @@
+package chessImager
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestSquareAt(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+
+	tests := []struct {
+		name     string
+		x, y     int
+		inverted bool
+		want     string
+		wantOk   bool
+	}{
+		{"a8", 24, 24, false, "a8", true},
+		{"h1", 623, 623, false, "h1", true},
+		{"e4", 24 + 4*75 + 10, 24 + 4*75 + 10, false, "e4", true},
+		{"inverted a8", 24, 24, true, "h1", true},
+		{"inverted h1", 623, 623, true, "a8", true},
+		{"border left", 23, 100, false, "", false},
+		{"border bottom", 100, 624, false, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := imager.SquareAt(nil, tt.x, tt.y, tt.inverted)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("SquareAt() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestSquareRect(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+
+	if got, want := imager.SquareRect(nil, "a8", false), image.Rect(24, 24, 99, 99); got != want {
+		t.Errorf("SquareRect() got = %v, want %v", got, want)
+	}
+	if got, want := imager.SquareRect(nil, "a8", true), image.Rect(549, 549, 624, 624); got != want {
+		t.Errorf("SquareRect() inverted got = %v, want %v", got, want)
+	}
+	if got := imager.SquareRect(nil, "i9", false); !got.Empty() {
+		t.Errorf("SquareRect() of invalid square got = %v, want empty rectangle", got)
+	}
+}
+
+func TestSquareAtImageBoard(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	imager.settings.Board.Type = boardTypeImage
+	imager.settings.Board.Image.Rect = Rectangle{X: 32, Y: 96, Width: 276, Height: 276}
+
+	for _, inverted := range []bool{false, true} {
+		for _, square := range []string{"a1", "a8", "d5", "h1", "h8"} {
+			rect := imager.SquareRect(nil, square, inverted)
+			center := rect.Min.Add(rect.Size().Div(2))
+			got, ok := imager.SquareAt(nil, center.X, center.Y, inverted)
+			if !ok || got != square {
+				t.Errorf("SquareAt(SquareRect(%v)) got = %v, %v", square, got, ok)
+			}
+		}
+	}
+
+	if _, ok := imager.SquareAt(nil, 31, 100, false); ok {
+		t.Errorf("SquareAt() outside image board returned ok")
+	}
+}
+
+func TestHTMLMap(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").AddAnnotation("e7", "!!")
+
+	m, err := imager.HTMLMap(ctx, "board", false)
+	if err != nil {
+		t.Fatalf("failed to create HTML map : %v", err)
+	}
+
+	if !strings.HasPrefix(m, `<map name="board">`) {
+		t.Errorf("HTMLMap() missing map element : %v", m)
+	}
+	if n := strings.Count(m, `shape="rect"`); n != 64 {
+		t.Errorf("HTMLMap() got %d square areas, want 64", n)
+	}
+	if n := strings.Count(m, `shape="circle"`); n != 1 {
+		t.Errorf("HTMLMap() got %d annotation areas, want 1", n)
+	}
+	if !strings.Contains(m, `coords="24,24,99,99" href="#a8"`) {
+		t.Errorf("HTMLMap() missing area for a8 : %v", m)
+	}
+	if strings.Index(m, `data-annotation="!!"`) > strings.Index(m, `shape="rect"`) {
+		t.Errorf("HTMLMap() annotations should come before squares")
+	}
+}
+
+func TestHTMLMapScaled(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+	ctx.Scale = 2
+
+	m, err := imager.HTMLMap(ctx, "board", true)
+	if err != nil {
+		t.Fatalf("failed to create HTML map : %v", err)
+	}
+
+	if !strings.Contains(m, `coords="48,48,198,198" href="#h1"`) {
+		t.Errorf("HTMLMap() missing scaled area for h1 : %v", m)
+	}
+	if imager.inverted {
+		t.Errorf("HTMLMap() changed the imager")
+	}
+}
+
+func TestSquareAtScaled(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").AddSettingsOverlay(`{"border": {"width": 10}}`)
+	ctx.Scale = 2
+
+	// The border is 20 pixels and the squares are 150 pixels, like in the rendered image
+	if got, want := imager.SquareRect(ctx, "h1", true), image.Rect(20, 20, 170, 170); got != want {
+		t.Errorf("SquareRect() got = %v, want %v", got, want)
+	}
+	if got, ok := imager.SquareAt(ctx, 100, 100, true); !ok || got != "h1" {
+		t.Errorf("SquareAt() got = %v, %v, want h1, true", got, ok)
+	}
+	if _, ok := imager.SquareAt(ctx, 15, 100, true); ok {
+		t.Errorf("SquareAt() in the scaled border returned ok")
+	}
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 1240, 1240); got != want {
+		t.Errorf("rendered image got size = %v, want %v", got, want)
+	}
+
+	ctx.AddSettingsOverlay(`{"border": {"width": "wide"}}`)
+	if got := imager.SquareRect(ctx, "h1", true); !got.Empty() {
+		t.Errorf("SquareRect() with an invalid overlay got = %v, want empty rectangle", got)
+	}
+}
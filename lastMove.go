@@ -0,0 +1,98 @@
+package chessImager
+
+import (
+	"errors"
+	"slices"
+)
+
+// inferMove returns the move that leads from the position before to the position after. The
+// move is found by comparing the boards, so the side to move in the FEN strings is not needed,
+// and castling rights and en passant squares are only checked if the FEN string of the position
+// before has them. Castling moves are returned as the move of the king, and promotions as the
+// move of the pawn.
+func inferMove(before, after *position) (from, to square, err error) {
+	// vacated are the squares that are empty after the move, and arrived are the squares
+	// that have a new piece after the move
+	var vacated, arrived []square
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			s := square{x, y}
+			if before.pieceAt(s) == after.pieceAt(s) {
+				continue
+			}
+			if after.pieceAt(s) == noPiece {
+				vacated = append(vacated, s)
+			} else {
+				arrived = append(arrived, s)
+			}
+		}
+	}
+
+	// The rook of a castling move, or the pawn captured en passant, is not part of the move
+	var castling, enPassant bool
+	switch {
+	case len(vacated) == 0 && len(arrived) == 0:
+		return from, to, errors.New("the positions are identical")
+	case len(vacated) == 1 && len(arrived) == 1:
+		from, to = vacated[0], arrived[0]
+	case len(vacated) == 2 && len(arrived) == 2:
+		castling = true
+		from, to = vacated[0], arrived[0]
+		if pieceKind(before.pieceAt(from)) != whiteKing {
+			from = vacated[1]
+		}
+		if pieceKind(after.pieceAt(to)) != whiteKing {
+			to = arrived[1]
+		}
+	case len(vacated) == 2 && len(arrived) == 1:
+		enPassant = true
+		from, to = vacated[0], arrived[0]
+		if from.x == to.x {
+			from = vacated[1]
+		}
+	default:
+		return from, to, errors.New("the positions differ by more than one move")
+	}
+
+	moved, landed := before.pieceAt(from), after.pieceAt(to)
+	promotion := pieceKind(moved) == whitePawn && isPromotionPiece(landed)
+	if moved == noPiece || isWhite(moved) != isWhite(landed) || (moved != landed && !promotion) {
+		return from, to, errors.New("the positions differ by more than one move")
+	}
+
+	// The castling rights and the en passant square are assumed, if the FEN string of the
+	// position before leaves them out, and the move must be legal in the position before
+	p := *before
+	p.whiteToMove = isWhite(moved)
+	if castling && p.fields < 3 {
+		p.castling = "KQkq"
+	}
+	if enPassant && p.fields < 4 {
+		p.enPassant, p.hasEnPassant = to, true
+	}
+	if !slices.Contains(p.legalMoves(from), to) {
+		return from, to, errors.New("the positions differ by more than one legal move")
+	}
+	if p.afterMove(from, to).board != withoutPromotion(after, to, promotion).board {
+		return from, to, errors.New("the positions differ by more than one move")
+	}
+
+	return from, to, nil
+}
+
+// isPromotionPiece returns true if a pawn can promote to the piece.
+func isPromotionPiece(p chessPiece) bool {
+	kind := pieceKind(p)
+	return p != noPiece && kind != whitePawn && kind != whiteKing
+}
+
+// withoutPromotion returns a copy of the position, where the piece that a pawn promoted to on
+// a square is replaced by a queen, which is the piece that afterMove promotes to.
+func withoutPromotion(p *position, s square, promotion bool) *position {
+	c := *p
+	if promotion {
+		c.board[s.x][s.y] = p.pieceAt(s) - pieceKind(p.pieceAt(s)) + whiteQueen
+	}
+
+	return &c
+}
@@ -0,0 +1,98 @@
+package chessImager
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_inferMove(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		before  string
+		after   string
+		want    string
+		wantErr bool
+	}{
+		{"pawn", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", "e2-e4", false},
+		{"capture", "4k3/8/8/3p4/4P3/8/8/4K3 w - -", "4k3/8/8/3P4/8/8/8/4K3 b - -", "e4-d5", false},
+		{"black move without side to move", "4k3/8/8/8/8/8/8/4K3", "3k4/8/8/8/8/8/8/4K3", "e8-d8", false},
+		{"kingside castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq -", "r3k2r/8/8/8/8/8/8/R4RK1 b kq -", "e1-g1", false},
+		{"queenside castling", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq -", "2kr3r/8/8/8/8/8/8/R3K2R w KQ -", "e8-c8", false},
+		{"castling without rights in fen", "r3k2r/8/8/8/8/8/8/R3K2R", "r3k2r/8/8/8/8/8/8/R4RK1", "e1-g1", false},
+		{"castling without rights", "r3k2r/8/8/8/8/8/8/R3K2R w kq -", "r3k2r/8/8/8/8/8/8/R4RK1 b kq -", "", true},
+		{"en passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6", "4k3/8/3P4/8/8/8/8/4K3 b - -", "e5-d6", false},
+		{"en passant without square in fen", "4k3/8/8/8/3pP3/8/8/4K3", "4k3/8/8/8/8/4p3/8/4K3", "d4-e3", false},
+		{"en passant without square", "4k3/8/8/3pP3/8/8/8/4K3 w - -", "4k3/8/3P4/8/8/8/8/4K3 b - -", "", true},
+		{"promotion", "8/4P3/8/8/8/8/8/k3K3 w - -", "4Q3/8/8/8/8/8/8/k3K3 b - -", "e7-e8", false},
+		{"underpromotion with capture", "3r4/4P3/8/8/8/8/8/k3K3 w - -", "3N4/8/8/8/8/8/8/k3K3 b - -", "e7-d8", false},
+		{"identical", "4k3/8/8/8/8/8/8/4K3", "4k3/8/8/8/8/8/8/4K3", "", true},
+		{"two moves", "4k3/8/8/8/8/8/8/4K3", "3k4/8/8/8/8/8/8/3K4", "", true},
+		{"illegal move", "4k3/8/8/8/8/8/8/N3K3", "4k3/8/8/8/8/8/8/4K2N", "", true},
+		{"moving into check", "4k3/8/8/8/8/8/r7/4K3", "4k3/8/8/8/8/8/r2K4/8", "", true},
+		{"piece changed", "4k3/8/8/8/8/8/8/N3K3", "4k3/8/8/8/8/8/8/B3K3", "", true},
+		{"promotion on wrong rank", "4k3/8/8/8/8/8/4P3/4K3", "4k3/8/8/8/8/4Q3/8/4K3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := newPosition(tt.before)
+			if err != nil {
+				t.Fatalf("newPosition() failed : %v", err)
+			}
+			after, err := newPosition(tt.after)
+			if err != nil {
+				t.Fatalf("newPosition() failed : %v", err)
+			}
+			from, to, err := inferMove(before, after)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inferMove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := fmt.Sprintf("%v-%v", from, to); !tt.wantErr && got != tt.want {
+				t.Errorf("inferMove() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastMove(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(legalMovesFen).
+		AddLastMoveFromPositions("r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3")
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "lastMove.png", &img)
+}
+
+func TestLastMoveCastlingArrow(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5N2/PPPP1PPP/RNBQ1RK1 b kq - 5 4").
+		AddLastMoveFromPositions(legalMovesFen).
+		AddSettingsOverlay(`{"last_move_style": {"arrow": true}}`)
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "lastMoveCastlingArrow.png", &img)
+}
+
+func TestLastMoveInvalid(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(legalMovesFen).AddLastMoveFromPositions(legalMovesFen)
+
+	_, err := imager.RenderWithContext(ctx)
+	if err == nil {
+		t.Errorf("RenderWithContext() expected an error for identical positions")
+	}
+}
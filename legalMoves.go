@@ -0,0 +1,192 @@
+package chessImager
+
+import (
+	"strings"
+)
+
+// legalMoves returns the squares that the piece on a square can legally move to, in the
+// position. Castling moves are returned as the square that the king moves to. The moves
+// are generated for the color of the piece, even if it is not that side's turn to move,
+// but en passant captures are only possible for the side to move.
+func (p *position) legalMoves(from square) []square {
+	piece := p.pieceAt(from)
+	if piece == noPiece {
+		return nil
+	}
+
+	var result []square
+	for _, to := range p.pseudoLegalMoves(from) {
+		if !p.leavesKingInCheck(from, to) {
+			result = append(result, to)
+		}
+	}
+
+	return result
+}
+
+// pseudoLegalMoves returns the moves of a piece, without checking if the king is left in check.
+// Castling moves are only returned if the king is not in check, and does not pass through
+// an attacked square.
+func (p *position) pseudoLegalMoves(from square) []square {
+	piece := p.pieceAt(from)
+	white := isWhite(piece)
+
+	var result []square
+	// canMoveTo returns true if the square is on the board, and not occupied by an own piece
+	canMoveTo := func(to square) bool {
+		return to.onBoard() && (p.pieceAt(to) == noPiece || isWhite(p.pieceAt(to)) != white)
+	}
+	steps := func(steps []square) {
+		for _, step := range steps {
+			if to := (square{from.x + step.x, from.y + step.y}); canMoveTo(to) {
+				result = append(result, to)
+			}
+		}
+	}
+	slide := func(dirs []square) {
+		for _, dir := range dirs {
+			to := square{from.x + dir.x, from.y + dir.y}
+			for canMoveTo(to) {
+				result = append(result, to)
+				if p.pieceAt(to) != noPiece {
+					break
+				}
+				to = square{to.x + dir.x, to.y + dir.y}
+			}
+		}
+	}
+
+	switch pieceKind(piece) {
+	case whitePawn:
+		result = p.pawnMoves(from, white)
+	case whiteKnight:
+		steps(knightSteps)
+	case whiteBishop:
+		slide(bishopDirs)
+	case whiteRook:
+		slide(rookDirs)
+	case whiteQueen:
+		slide(queenDirs)
+	case whiteKing:
+		steps(kingSteps)
+		result = append(result, p.castlingMoves(from, white)...)
+	}
+
+	return result
+}
+
+// pawnMoves returns the moves of a pawn, including en passant captures. Promotions are
+// returned as the square that the pawn moves to.
+func (p *position) pawnMoves(from square, white bool) []square {
+	dy, start := 1, 1
+	if !white {
+		dy, start = -1, 6
+	}
+
+	var result []square
+	one := square{from.x, from.y + dy}
+	if one.onBoard() && p.pieceAt(one) == noPiece {
+		result = append(result, one)
+		two := square{from.x, from.y + 2*dy}
+		if from.y == start && p.pieceAt(two) == noPiece {
+			result = append(result, two)
+		}
+	}
+
+	for _, dx := range pawnCaptureDx {
+		to := square{from.x + dx, from.y + dy}
+		if !to.onBoard() {
+			continue
+		}
+		target := p.pieceAt(to)
+		if target != noPiece && isWhite(target) != white {
+			result = append(result, to)
+		} else if p.isEnPassant(from, to) {
+			result = append(result, to)
+		}
+	}
+
+	return result
+}
+
+// isEnPassant returns true if a pawn move from one square to another is an en passant capture.
+func (p *position) isEnPassant(from, to square) bool {
+	piece := p.pieceAt(from)
+	return pieceKind(piece) == whitePawn && isWhite(piece) == p.whiteToMove && p.hasEnPassant &&
+		to == p.enPassant && from.x != to.x && p.pieceAt(to) == noPiece
+}
+
+// castlingMoves returns the squares that the king can move to by castling.
+func (p *position) castlingMoves(from square, white bool) []square {
+	rank, king, queen, rook := 0, "K", "Q", whiteRook
+	if !white {
+		rank, king, queen, rook = 7, "k", "q", blackRook
+	}
+	if from != (square{4, rank}) || p.isAttacked(from, white) {
+		return nil
+	}
+
+	var result []square
+	castle := func(right string, rookX int, empty, safe []int) {
+		if !strings.Contains(p.castling, right) || p.board[rookX][rank] != rook {
+			return
+		}
+		for _, x := range empty {
+			if p.board[x][rank] != noPiece {
+				return
+			}
+		}
+		for _, x := range safe {
+			if p.isAttacked(square{x, rank}, white) {
+				return
+			}
+		}
+		result = append(result, square{safe[len(safe)-1], rank})
+	}
+	castle(king, 7, []int{5, 6}, []int{5, 6})
+	castle(queen, 0, []int{1, 2, 3}, []int{3, 2})
+
+	return result
+}
+
+// leavesKingInCheck returns true if the move leaves the king of the moving side in check.
+func (p *position) leavesKingInCheck(from, to square) bool {
+	white := isWhite(p.pieceAt(from))
+	after := p.afterMove(from, to)
+	king, ok := after.king(white)
+
+	return ok && after.isAttacked(king, white)
+}
+
+// afterMove returns a copy of the position, after a move. Castling moves also move the rook,
+// en passant captures remove the captured pawn, and pawns that promote become queens.
+func (p *position) afterMove(from, to square) *position {
+	after := *p
+	piece := p.pieceAt(from)
+
+	switch {
+	case p.isEnPassant(from, to):
+		after.board[to.x][from.y] = noPiece
+	case pieceKind(piece) == whiteKing && abs(to.x-from.x) == 2:
+		rookFrom, rookTo := 7, 5
+		if to.x < from.x {
+			rookFrom, rookTo = 0, 3
+		}
+		after.board[rookTo][from.y] = after.board[rookFrom][from.y]
+		after.board[rookFrom][from.y] = noPiece
+	case pieceKind(piece) == whitePawn && (to.y == 0 || to.y == 7):
+		piece += whiteQueen - whitePawn
+	}
+
+	after.board[to.x][to.y] = piece
+	after.board[from.x][from.y] = noPiece
+	after.whiteToMove = !isWhite(piece)
+	after.hasEnPassant = false
+
+	return &after
+}
+
+// isAttacked returns true if a square is attacked by the opponent of a side.
+func (p *position) isAttacked(s square, white bool) bool {
+	return len(p.attackers(s, !white)) > 0
+}
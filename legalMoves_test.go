@@ -0,0 +1,139 @@
+package chessImager
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// legalMovesFen is the italian game, where white can castle and the knight on f3 can capture on e5
+const legalMovesFen = "r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4"
+
+func Test_legalMoves(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fen  string
+		from square
+		want string
+	}{
+		{"knight", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", square{6, 0}, "[f3 h3]"},
+		{"pawn", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", square{4, 1}, "[e3 e4]"},
+		{"pinned rook", "4r1k1/8/8/8/8/8/4R3/4K3 w - -", square{4, 1}, "[e3 e4 e5 e6 e7 e8]"},
+		{"king in check", "4k3/8/8/8/8/8/8/r3K3 w - -", square{4, 0}, "[d2 e2 f2]"},
+		{"castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq -", square{4, 0}, "[c1 d1 d2 e2 f1 f2 g1]"},
+		{"castling through check", "r3k2r/8/b7/8/8/8/8/R3K2R w KQkq -", square{4, 0}, "[c1 d1 d2 f2]"},
+		{"castling without rights", "r3k2r/8/8/8/8/8/8/R3K2R b Kq -", square{4, 7}, "[c8 d7 d8 e7 f7 f8]"},
+		{"en passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6", square{4, 4}, "[d6 e6]"},
+		{"en passant exposes king", "8/8/8/K2pP2r/8/8/8/4k3 w - d6", square{4, 4}, "[e6]"},
+		{"promotion", "8/4P3/8/8/8/8/8/k3K3 w - -", square{4, 6}, "[e8]"},
+		{"empty square", "4k3/8/8/8/8/8/8/4K3 w - -", square{0, 0}, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, err := newPosition(tt.fen)
+			if err != nil {
+				t.Fatalf("newPosition() failed : %v", err)
+			}
+			moves := pos.legalMoves(tt.from)
+			slices.SortFunc(moves, func(a, b square) int {
+				return strings.Compare(a.String(), b.String())
+			})
+			if got := fmt.Sprint(moves); got != tt.want {
+				t.Errorf("legalMoves(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newPosition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fen     string
+		wantErr bool
+	}{
+		{"board only", "4k3/8/8/8/8/8/8/4K3", false},
+		{"full fen", legalMovesFen, false},
+		{"invalid side to move", "4k3/8/8/8/8/8/8/4K3 x - -", true},
+		{"invalid castling rights", "4k3/8/8/8/8/8/8/4K3 w KX -", true},
+		{"invalid en passant square", "4k3/8/8/8/8/8/8/4K3 w - z9", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPosition(tt.fen)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newPosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLegalMoves(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(legalMovesFen).ShowLegalMoves("f3")
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "legalMoves.png", &img)
+}
+
+func TestLegalMovesCastling(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(legalMovesFen).ShowLegalMoves("e1")
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "legalMovesCastling.png", &img)
+}
+
+func TestLegalMovesEmptySquare(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(legalMovesFen).ShowLegalMoves("e3")
+
+	_, err := imager.RenderWithContext(ctx)
+	if err == nil {
+		t.Errorf("RenderWithContext() expected an error for an empty square")
+	}
+}
+
+func TestLegalMovesEnPassant(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	red, green := hexMust(t, "#FF0000"), hexMust(t, "#00FF00")
+	style := imager.settings.LegalMovesStyle
+	style.MoveStyle = HighlightStyle{Type: HighlightTypeFull, Color: ColorRGBA{RGBA: green}}
+	style.CaptureStyle = HighlightStyle{Type: HighlightTypeFull, Color: ColorRGBA{RGBA: red}}
+	ctx := imager.NewContext("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1").ShowLegalMovesWithStyle("e5", &style)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+
+	// d6 is an en passant capture, and e6 is a normal move
+	for _, tt := range []struct {
+		square string
+		want   color.RGBA
+	}{{"d6", red}, {"e6", green}} {
+		r := imager.SquareRect(nil, tt.square, false)
+		if got := color.RGBAModel.Convert(img.At(r.Min.X+5, r.Min.Y+5)); got != tt.want {
+			t.Errorf("%s got color = %v, want %v", tt.square, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package chessImager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ApplySettings deep merges a partial JSON settings document (an overlay) into the current
+// settings. Only the fields in the overlay are changed, objects are merged field by field,
+// while lists (like order and pieces) are replaced. An explicit null unsets a field, which
+// gives it the zero value (0, "", false, an empty list or no object), and not the value of
+// the default settings or the theme. The resulting settings are validated, see Settings.Validate.
+//
+// Example : {"border": {"color": "#FF0000"}, "highlight_style": {"factor": null}}
+func (i *Imager) ApplySettings(overlay io.Reader) error {
+	s, err := i.settings.merge(overlay)
+	if err != nil {
+		return err
+	}
+	c := *i
+	c.settings = s
+	if err = s.validate(c.assetExists); err != nil {
+		return err
+	}
+
+	i.settings = s
+
+	return nil
+}
+
+// ApplySettingsFromPath deep merges a partial JSON, YAML or TOML settings file into
+// the current settings. The format is chosen by the file extension, see LoadSettings.
+// See ApplySettings for details.
+func (i *Imager) ApplySettingsFromPath(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	data, err = settingsToJSON(path, data)
+	if err != nil {
+		return err
+	}
+
+	return i.ApplySettings(bytes.NewReader(data))
+}
+
+// withOverlays returns a copy of the imager, where the settings overlays have been applied.
+// The resulting settings are validated. If there are no overlays, the imager itself is returned.
+func (i *Imager) withOverlays(overlays []string) (*Imager, error) {
+	if len(overlays) == 0 {
+		return i, nil
+	}
+
+	s := i.settings
+	for _, overlay := range overlays {
+		var err error
+		s, err = s.merge(strings.NewReader(overlay))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := *i
+	c.settings = s
+	if err := s.validate(c.assetExists); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// merge returns a copy of the settings, with a partial JSON settings document deep merged into it.
+func (s *Settings) merge(overlay io.Reader) (*Settings, error) {
+	var o map[string]any
+	d := json.NewDecoder(overlay)
+	d.UseNumber()
+	if err := d.Decode(&o); err != nil {
+		return nil, fmt.Errorf("invalid settings overlay : %v", err)
+	}
+	if o == nil {
+		return nil, errors.New("invalid settings overlay : must be a JSON object")
+	}
+	// The schema reference is only used by editors, see SettingsSchema
+	delete(o, "$schema")
+
+	base, err := s.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(mergeMaps(base, o))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSettings(bytes.NewReader(data))
+}
+
+// toMap converts the settings to a generic JSON object.
+func (s *Settings) toMap() (map[string]any, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err = d.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// mergeMaps deep merges overlay into base, and returns base. Objects are merged
+// recursively, a null value removes the key (so the field gets its zero value when
+// the map is decoded), and all other values are replaced.
+func mergeMaps(base, overlay map[string]any) map[string]any {
+	for key, value := range overlay {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+
+		bm, ok1 := base[key].(map[string]any)
+		om, ok2 := value.(map[string]any)
+		if ok1 && ok2 {
+			base[key] = mergeMaps(bm, om)
+		} else {
+			base[key] = value
+		}
+	}
+
+	return base
+}
+
+// resolvePalette sets all colors that are palette references ("$name") to the palette
+// colors. The references are kept, so that the colors follow later changes to the palette.
+// Palette colors can reference other palette colors.
+func (s *Settings) resolvePalette() error {
+	var resolve func(ref string, seen map[string]bool) (color.RGBA, error)
+	resolve = func(ref string, seen map[string]bool) (color.RGBA, error) {
+		name := strings.TrimPrefix(ref, "$")
+		value, ok := s.Palette[name]
+		switch {
+		case !ok:
+			return color.RGBA{}, fmt.Errorf("invalid color (%s) : unknown palette color", ref)
+		case seen[name]:
+			return color.RGBA{}, fmt.Errorf("invalid color (%s) : circular palette reference", ref)
+		}
+		if value.ref != "" {
+			seen[name] = true
+			return resolve(value.ref, seen)
+		}
+		return value.RGBA, nil
+	}
+
+	// Walk all the color settings, using the types of the Settings struct
+	var walk func(v reflect.Value, path string) error
+	walk = func(v reflect.Value, path string) error {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if !v.IsNil() {
+				return walk(v.Elem(), path)
+			}
+		case reflect.Struct:
+			if v.Type() == colorType {
+				c := v.Addr().Interface().(*ColorRGBA)
+				if c.ref == "" {
+					return nil
+				}
+				col, err := resolve(c.ref, map[string]bool{})
+				if err != nil {
+					return fmt.Errorf("%s: %v", path, err)
+				}
+				c.RGBA = col
+				return nil
+			}
+			for n := 0; n < v.NumField(); n++ {
+				if !v.Type().Field(n).IsExported() {
+					continue
+				}
+				name := strings.Split(v.Type().Field(n).Tag.Get("json"), ",")[0]
+				if err := walk(v.Field(n), strings.TrimPrefix(path+"."+name, ".")); err != nil {
+					return err
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for n := 0; n < v.Len(); n++ {
+				if err := walk(v.Index(n), fmt.Sprintf("%s[%d]", path, n)); err != nil {
+					return err
+				}
+			}
+		case reflect.Map:
+			// Map values are not addressable, so they are resolved in a copy
+			for _, key := range v.MapKeys() {
+				value := reflect.New(v.Type().Elem()).Elem()
+				value.Set(v.MapIndex(key))
+				if err := walk(value, fmt.Sprintf("%s.%v", path, key)); err != nil {
+					return err
+				}
+				v.SetMapIndex(key, value)
+			}
+		}
+		return nil
+	}
+
+	return walk(reflect.ValueOf(s).Elem(), "")
+}
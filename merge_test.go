@@ -0,0 +1,152 @@
+package chessImager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplySettings(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(`{"border": {"color": "#FF0000"}, "order": [0,1,2,4,3,5,6]}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	want := NewImager().settings
+	want.Border.Color = ColorRGBA{RGBA: hexMust(t, "#FF0000")}
+	want.Order = []int{0, 1, 2, 4, 3, 5, 6}
+	if !reflect.DeepEqual(imager.settings, want) {
+		t.Errorf("ApplySettings() got = %+v, want %+v", imager.settings, want)
+	}
+}
+
+func TestApplySettingsNull(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(`{"highlight_style": {"factor": null}, "font_style": null}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	if imager.settings.HighlightStyle.Factor != 0 {
+		t.Errorf("null did not unset highlight_style.factor")
+	}
+	if imager.settings.HighlightStyle.Width != 4 {
+		t.Errorf("null unset too much, highlight_style.width = %v", imager.settings.HighlightStyle.Width)
+	}
+}
+
+func TestApplySettingsNullNonZeroDefault(t *testing.T) {
+	t.Parallel()
+
+	// Both fields have a value in the default settings, and null gives them the zero value
+	imager := NewImager()
+	err := imager.ApplySettings(strings.NewReader(
+		`{"border": {"width": null}, "tactics_style": {"discovered_attack_style": {"dash": null}}}`))
+	if err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	defaults := NewImager().settings
+	if defaults.Border.Width == 0 || len(defaults.TacticsStyle.DiscoveredAttackStyle.Dash) == 0 {
+		t.Fatalf("the default settings have changed, the test needs fields with values")
+	}
+	if imager.settings.Border.Width != 0 {
+		t.Errorf("null got border.width = %v, want 0", imager.settings.Border.Width)
+	}
+	if dash := imager.settings.TacticsStyle.DiscoveredAttackStyle.Dash; dash != nil {
+		t.Errorf("null got tactics_style.discovered_attack_style.dash = %v, want nil", dash)
+	}
+	if imager.settings.Border.Color != defaults.Border.Color {
+		t.Errorf("null unset too much, border.color = %v", imager.settings.Border.Color)
+	}
+}
+
+func TestApplySettingsInvalid(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	for _, overlay := range []string{`[1,2]`, `null`, `{"border":`, `{"border": {"width": "wide"}}`} {
+		if err := imager.ApplySettings(strings.NewReader(overlay)); err == nil {
+			t.Errorf("ApplySettings(%s) returned no error", overlay)
+		}
+	}
+}
+
+func TestLoadPartialSettings(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "partial.json")
+	err := os.WriteFile(path, []byte(`{"border": {"color": "#123456"}}`), 0600)
+	if err != nil {
+		t.Fatalf("failed to write settings file : %v", err)
+	}
+
+	imager, err := NewImagerFromPath(path)
+	if err != nil {
+		t.Fatalf("NewImagerFromPath() failed : %v", err)
+	}
+
+	// Everything except the border color should come from the defaults
+	want := NewImager().settings
+	want.Border.Color = ColorRGBA{RGBA: hexMust(t, "#123456")}
+	if !reflect.DeepEqual(imager.settings, want) {
+		t.Errorf("partial settings got = %+v, want %+v", imager.settings, want)
+	}
+}
+
+func TestThemeLayers(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	if err := imager.UseTheme("brown"); err != nil {
+		t.Fatalf("UseTheme() failed : %v", err)
+	}
+	if err := imager.ApplySettings(strings.NewReader(`{"board": {"default": {"white": "#FFFFFF"}}}`)); err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+
+	theme, _ := loadTheme("brown")
+	if imager.settings.Board.Default.Black != theme.Board.Default.Black {
+		t.Errorf("override changed the theme black color")
+	}
+	if imager.settings.Board.Default.White != (ColorRGBA{RGBA: hexMust(t, "#FFFFFF")}) {
+		t.Errorf("override did not change the white color")
+	}
+}
+
+func TestContextSettingsOverlay(t *testing.T) {
+	t.Parallel()
+
+	const fen = "8/8/8/8/8/8/8/8 w - - 0 1"
+	imager := NewImager()
+	ctx := imager.NewContext(fen).AddSettingsOverlay(`{"border": {"width": 10}}`)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+	if got := img.Bounds().Dx(); got != 620 {
+		t.Errorf("overlay got width = %v, want 620", got)
+	}
+
+	// The imager should not be changed
+	if imager.settings.Border.Width != 24 {
+		t.Errorf("overlay changed the imager settings")
+	}
+	img, _ = imager.Render(fen)
+	if got := img.Bounds().Dx(); got != 648 {
+		t.Errorf("render after overlay got width = %v, want 648", got)
+	}
+
+	ctx = imager.NewContext(fen).AddSettingsOverlay(`not json`)
+	if _, err = imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("invalid overlay returned no error")
+	}
+}
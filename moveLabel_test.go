@@ -0,0 +1,110 @@
+package chessImager
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMoveLabels(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQK2R w KQkq - 0 1"
+	ctx := imager.NewContext(fen)
+
+	head, err := ctx.NewMoveStyle(MoveTypeArrow, "#1565C0CC", "#1565C0CC", 0.15, 10)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	head.LabelPosition = MoveLabelPositionHead
+	head.WeightWidth, head.WeightOpacity = 0.5, 0.5
+	head.Curve = -0.2
+
+	ctx.AddLabeledMove("e2", "e4", "1", 1).
+		AddLabeledMove("d2", "d4", "+0.31", 0.7).
+		AddLabeledMove("c2", "c4", "3", 0.3).
+		AddLabeledMoveWithStyle("g1", "f3", "42%", 0.4, head).
+		AddLabeledMove("b1", "c3", "5", 0.2).
+		AddLabeledMove("0-0", "", "O-O", 0.5)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "moveLabels.png", &img)
+}
+
+func TestMoveStyle_weighted(t *testing.T) {
+	t.Parallel()
+
+	style := &MoveStyle{Factor: 0.2, WeightWidth: 0.5, WeightOpacity: 1}
+	style.Color.A = 200
+	style.Color2.A = 100
+
+	tests := []struct {
+		weight float64
+		factor float64
+		alpha  uint8
+		alpha2 uint8
+	}{
+		{1, 0.2, 200, 100},
+		{0.5, 0.15, 100, 50},
+		{0, 0.1, 0, 0},
+		{2, 0.2, 200, 100}, // Clamped to 1
+		{-1, 0.1, 0, 0},    // Clamped to 0
+	}
+	for _, tt := range tests {
+		got := style.weighted(tt.weight)
+		if math.Abs(got.Factor-tt.factor) > 1e-9 || got.Color.A != tt.alpha || got.Color2.A != tt.alpha2 {
+			t.Errorf("weighted(%v) = %v, %v, %v, want %v, %v, %v", tt.weight, got.Factor, got.Color.A,
+				got.Color2.A, tt.factor, tt.alpha, tt.alpha2)
+		}
+	}
+	if style.Factor != 0.2 {
+		t.Errorf("weighted() changed the original style")
+	}
+}
+
+func TestMoveWeightZero(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	r := &rendererMoves{Imager: imager}
+	style := &imager.settings.MoveStyle
+	zero := 0.0
+	moves := r.layoutStyledMoves([]layoutMove{
+		{Move: Move{From: "e2", To: "e4"}, style: style},
+		{Move: Move{From: "d2", To: "d4", Weight: &zero}, style: style},
+	})
+	if len(moves) != 2 {
+		t.Fatalf("layoutStyledMoves() got %d moves, want 2", len(moves))
+	}
+	if moves[0].style.Factor != style.Factor {
+		t.Errorf("move without weight got factor = %v, want %v", moves[0].style.Factor, style.Factor)
+	}
+	if want := style.weighted(0).Factor; moves[1].style.Factor != want {
+		t.Errorf("move with weight 0 got factor = %v, want %v", moves[1].style.Factor, want)
+	}
+}
+
+func TestMoveLabelsOffBoard(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "r1bqkb1r/pppp1ppp/2n2n2/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 0 1"
+	ctx := imager.NewContext(fen)
+
+	dots, err := ctx.NewMoveStyle(MoveTypeDots, "#88000088", "#000000", 0.25, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	ctx.AddLabeledMove("right:h5", "g5", "N@g5", 1).
+		AddLabeledMoveWithStyle("left:a4", "c4", "P@c4", 1, dots).
+		AddLabeledMove("e4", "bottom:e1", "xe4", 1)
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "moveLabelsOffBoard.png", &img)
+}
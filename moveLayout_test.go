@@ -0,0 +1,121 @@
+package chessImager
+
+import (
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+func TestMoveLayout(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "8/8/8/8/4k3/8/8/R3K3 w - - 0 1"
+	ctx := imager.NewContext(fen)
+
+	style, err := ctx.NewMoveStyle(MoveTypeArrow, "#00008888", "#000000", 0.2, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	ctx.AddMove("a1", "a4").AddMove("a1", "a8").AddMove("a6", "a2"). // Three lanes
+										AddMoveWithStyle("c2", "e4", style).AddMoveWithStyle("d3", "e4", style). // Two lanes
+										AddMoveWithStyle("e1", "e3", style).AddMoveWithStyle("e1", "e3", style). // Duplicates
+										AddMove("h1", "h4").AddMove("h4", "h8")                                  // Touching, not overlapping
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "moveLayout.png", &img)
+}
+
+func TestMoveLayoutConverging(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "8/8/8/8/4k3/8/8/4K3 w - - 0 1"
+	ctx := imager.NewContext(fen)
+
+	style, err := ctx.NewMoveStyle(MoveTypeArrow, "#00008888", "#000000", 0.15, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	style.Outline = &MoveOutline{Color: ColorRGBA{RGBA: hexMust(t, "#FFFFFF")}, Width: 2}
+	ctx.AddMove("a4", "e4").AddMove("d2", "e4"). // Rook and knight from the left
+							AddMove("c3", "e4").AddMoveWithStyle("g3", "e4", style).AddMove("e1", "e4"). // Two knights and a rook from below
+							AddMovePath("h2-h4-e4").AddMove("g4", "e4").                                 // Path and rook from the right
+							AddMove("b7", "e4")                                                          // Bishop, alone
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "moveLayoutConverging.png", &img)
+}
+
+func Test_layoutMoves(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	r := &rendererMoves{Imager: imager, gg: gg.NewContext(600, 600)}
+	width := r.getSquareBox(0, 0).shrink(imager.settings.MoveStyle.Factor).Width
+	spacing := width * laneSpacing
+
+	tests := []struct {
+		name    string
+		moves   []Move
+		offsets []float64
+	}{
+		{"single", []Move{{From: "e2", To: "e4"}}, []float64{0}},
+		{"duplicates", []Move{{From: "e2", To: "e4"}, {From: "e2", To: "e4"}}, []float64{0}},
+		{"touching", []Move{{From: "a1", To: "a4"}, {From: "a4", To: "a8"}}, []float64{0, 0}},
+		{"parallel", []Move{{From: "a1", To: "a4"}, {From: "b1", To: "b4"}}, []float64{0, 0}},
+		{"crossing", []Move{{From: "a1", To: "h8"}, {From: "h1", To: "a8"}}, []float64{0, 0}},
+		{"same target", []Move{{From: "c2", To: "e4"}, {From: "d3", To: "e4"}}, []float64{-spacing / 2, spacing / 2}},
+		{"opposite", []Move{{From: "a1", To: "a4"}, {From: "a4", To: "a1"}}, []float64{-spacing / 2, -spacing / 2}},
+		{"chain", []Move{{From: "a1", To: "a3"}, {From: "h1", To: "h2"}, {From: "a2", To: "a5"}, {From: "a4", To: "a8"}},
+			[]float64{-spacing, 0, 0, spacing}},
+		{"knight", []Move{{From: "g1", To: "f3"}, {From: "g1", To: "g3"}}, []float64{0, 0}},
+		{"knight and rook", []Move{{From: "d2", To: "e4"}, {From: "a4", To: "e4"}}, []float64{-spacing / 2, spacing / 2}},
+		{"knights", []Move{{From: "c3", To: "e4"}, {From: "g3", To: "e4"}, {From: "e1", To: "e4"}},
+			[]float64{-spacing, 0, spacing}},
+		{"path", []Move{{From: "a2", Via: []string{"a4"}, To: "e4"}, {From: "b4", To: "e4"}},
+			[]float64{-spacing / 2, spacing / 2}},
+		{"converging", []Move{{From: "e1", To: "e4"}, {From: "a4", To: "e4"}, {From: "b1", To: "e4"}, {From: "f6", To: "e4"},
+			{From: "c5", To: "e4"}}, []float64{0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		moves := r.layoutMoves(tt.moves)
+		if len(moves) != len(tt.offsets) {
+			t.Errorf("%s : got %d moves, want %d", tt.name, len(moves), len(tt.offsets))
+			continue
+		}
+		for n, m := range moves {
+			if m.offset != tt.offsets[n] {
+				t.Errorf("%s : move %d offset = %v, want %v", tt.name, n, m.offset, tt.offsets[n])
+			}
+		}
+	}
+}
+
+func TestMovesInverted(t *testing.T) {
+	t.Parallel()
+
+	filename := "movesInvertedArrows.png"
+
+	const fen = "r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4"
+	imager := NewImager()
+	ctx := imager.NewContext(fen)
+	ctx.AddMove("e1", "g1")
+	ctx.AddMove("f3", "g5")
+	ctx.AddMove("c4", "f7")
+	ctx.AddMove("h2", "h4")
+
+	// Render the image
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+
+	compareImages(t, filename, &img)
+}
@@ -0,0 +1,52 @@
+package chessImager
+
+import (
+	"testing"
+)
+
+func TestMovePaths(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "8/8/8/4k3/8/8/8/R3K3 w - - 0 1"
+	ctx := imager.NewContext(fen)
+
+	curved, err := ctx.NewMoveStyle(MoveTypeArrow, "#00008888", "#000000", 0.25, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+	curved.Curve = 0.3
+	dots, err := ctx.NewMoveStyle(MoveTypeDots, "#88000088", "#000000", 0.25, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+
+	ctx.AddMovePath("a1-a4-h4-h8").
+		AddMoveWithStyle("e1", "c4", curved).
+		AddMovePathWithStyle("e1-g2-f4", curved).
+		AddMovePathWithStyle("e5-b5-b8", dots)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "movePaths.png", &img)
+
+	img, err = imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "movePathsInverted.png", &img)
+}
+
+func TestMovePathInvalidSquare(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+	ctx.AddMovePath("a1-0-0-h8")
+
+	if _, err := imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("RenderWithContext() expected an error for a castling square in a path")
+	}
+}
@@ -0,0 +1,80 @@
+package chessImager
+
+import (
+	"testing"
+)
+
+func TestMoveShapes(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "r3k2r/8/8/8/8/8/8/4K3 b kq - 0 1"
+	ctx := imager.NewContext(fen)
+
+	newStyle := func(color string) *MoveStyle {
+		style, err := ctx.NewMoveStyle(MoveTypeArrow, color, "#88000099", 0.15, 10)
+		if err != nil {
+			t.Fatalf("NewMoveStyle() failed : %v", err)
+		}
+		return style
+	}
+
+	outlined := newStyle("#FFD700CC")
+	outlined.Outline = &MoveOutline{Width: 3, Color: ColorRGBA{RGBA: hexMust(t, "#000000FF")}}
+	dashed := newStyle("#1565C0CC")
+	dashed.Dash = []float64{12, 8}
+	dashed.RoundTail = true
+	open := newStyle("#2E7D32CC")
+	open.HeadType = MoveHeadTypeOpen
+	open.HeadLength, open.HeadWidth = 1.5, 3
+	exchange := newStyle("#C62828CC")
+	exchange.DoubleHead = true
+	knight := newStyle("#6A1B9ACC")
+	knight.Outline = &MoveOutline{Width: 2, Color: ColorRGBA{RGBA: hexMust(t, "#FFFFFFFF")}}
+	knight.Dash = []float64{10}
+	castling := newStyle("#1565C0CC")
+	castling.Dash = []float64{8, 6}
+	castling.HeadType = MoveHeadTypeOpen
+
+	ctx.AddMoveWithStyle("a2", "a6", outlined).
+		AddMoveWithStyle("b2", "b6", dashed).
+		AddMoveWithStyle("c2", "c6", open).
+		AddMoveWithStyle("d3", "g6", exchange).
+		AddMoveWithStyle("e1", "f3", knight).
+		AddMoveWithStyle("g2", "e3", knight).
+		AddMoveWithStyle("", "0-0-0", castling)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "moveShapes.png", &img)
+}
+
+func Test_pathSection(t *testing.T) {
+	t.Parallel()
+
+	path := []point{{0, 0}, {10, 0}, {10, 10}}
+	tests := []struct {
+		from, to float64
+		want     []point
+	}{
+		{0, 20, []point{{0, 0}, {10, 0}, {10, 10}}},
+		{5, 15, []point{{5, 0}, {10, 0}, {10, 5}}},
+		{-2, 22, []point{{-2, 0}, {10, 0}, {10, 12}}},
+		{2, 8, []point{{2, 0}, {8, 0}}},
+	}
+	for _, tt := range tests {
+		got := pathSection(path, tt.from, tt.to)
+		if len(got) != len(tt.want) {
+			t.Errorf("pathSection(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			continue
+		}
+		for n := range got {
+			if got[n].sub(tt.want[n]).length() > 1e-9 {
+				t.Errorf("pathSection(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+				break
+			}
+		}
+	}
+}
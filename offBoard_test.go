@@ -0,0 +1,83 @@
+package chessImager
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOffBoard(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "r1bqkb1r/pppp1ppp/2n2n2/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 0 1"
+	ctx := imager.NewContext(fen)
+
+	pocket, err := ctx.NewHighlightStyle(HighlightTypeFull, "#FFD70099", 0, 0)
+	if err != nil {
+		t.Fatalf("NewHighlightStyle() failed : %v", err)
+	}
+	dots, err := ctx.NewMoveStyle(MoveTypeDots, "#88000088", "#000000", 0.25, 0)
+	if err != nil {
+		t.Fatalf("NewMoveStyle() failed : %v", err)
+	}
+
+	ctx.AddHighlightWithStyle("right:h5", pocket).
+		AddHighlightWithStyle("top:d8", pocket).
+		AddMove("right:h5", "g5").
+		AddMove("top:d8", "d6").
+		AddMoveWithStyle("left:a4", "c4", dots).
+		AddMove("e4", "bottom:e1")
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "offBoard.png", &img)
+
+	img, err = imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "offBoardInverted.png", &img)
+}
+
+func TestOffBoardAnnotation(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "8/8/8/8/8/8/8/8 w - - 0 1"
+	ctx := imager.NewContext(fen).AddAnnotation("right:h5", "3").AddAnnotation("top:d8", "!")
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "offBoardAnnotation.png", &img)
+
+	if _, err = imager.HTMLMap(ctx, "board", true); err != nil {
+		t.Errorf("HTMLMap() failed : %v", err)
+	}
+}
+
+func TestOffBoardAnnotationNoBorder(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	if err := imager.ApplySettings(strings.NewReader(`{"border": {"width": 0}}`)); err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").AddAnnotation("right:h5", "!")
+	if _, err := imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("RenderWithContext() expected an error for an off-board annotation without a border")
+	}
+}
+
+func TestAnnotationCastling(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").AddAnnotation("0-0", "!")
+	if _, err := imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("RenderWithContext() expected an error for a castling annotation")
+	}
+}
@@ -0,0 +1,87 @@
+package chessImager
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPieceStyles(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	ctx := imager.NewContext(fen)
+
+	hanging, err := ctx.NewPieceStyle(1, "rgba(255, 0, 0, 0.6)", false)
+	if err != nil {
+		t.Fatalf("NewPieceStyle() failed : %v", err)
+	}
+	gray, err := ctx.NewPieceStyle(1, "", true)
+	if err != nil {
+		t.Fatalf("NewPieceStyle() failed : %v", err)
+	}
+	ctx.AddPieceStyle("d4", nil).
+		AddPieceStyle("f4", hanging).
+		AddPieceStyle("a5", gray).
+		AddPieceStyle("e4", hanging). // No piece on e4, ignored
+		AddGhostPiece("c6", "WN", &PieceStyle{Opacity: 0.4}).
+		AddGhostPiece("e2", "bk", nil)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "pieceStyles.png", &img)
+
+	img, err = imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "pieceStylesInverted.png", &img)
+}
+
+func TestPieceStylesInvalid(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "8/8/8/8/8/8/8/8 w - - 0 1"
+	for _, ctx := range []*ImageContext{
+		imager.NewContext(fen).AddPieceStyle("i9", nil),
+		imager.NewContext(fen).AddPieceStyle("0-0", nil),
+		imager.NewContext(fen).AddGhostPiece("e4", "WX", nil),
+	} {
+		if _, err := imager.RenderWithContext(ctx); err == nil {
+			t.Errorf("RenderWithContext(%+v) returned no error", ctx.Pieces)
+		}
+	}
+	if _, err := imager.NewContext(fen).NewPieceStyle(1, "nocolor", false); err == nil {
+		t.Errorf("NewPieceStyle() returned no error for an invalid tint")
+	}
+}
+
+func TestApplyPieceStyle(t *testing.T) {
+	t.Parallel()
+
+	piece := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	piece.SetRGBA(0, 0, color.RGBA{R: 200, G: 100, B: 0, A: 255})
+
+	tests := []struct {
+		name  string
+		style PieceStyle
+		want  color.RGBA
+	}{
+		{"opacity", PieceStyle{Opacity: 0.5}, color.RGBA{R: 100, G: 50, B: 0, A: 128}},
+		{"grayscale", PieceStyle{Opacity: 1, Grayscale: true}, color.RGBA{R: 118, G: 118, B: 118, A: 255}},
+		{"tint", PieceStyle{Opacity: 1, Tint: ColorRGBA{RGBA: color.RGBA{B: 255, A: 255}}}, color.RGBA{B: 255, A: 255}},
+		{"half tint", PieceStyle{Opacity: 1, Tint: ColorRGBA{RGBA: color.RGBA{B: 200, A: 128}}}, color.RGBA{R: 100, G: 50, B: 100, A: 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applyPieceStyle(piece, &tt.style).At(0, 0)
+			if got != tt.want {
+				t.Errorf("applyPieceStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package chessImager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// square is a square on the board, where x is the file (a=0) and y is the rank (1=0)
+type square struct {
+	x, y int
+}
+
+// onBoard returns true if the square is on the board.
+func (s square) onBoard() bool {
+	return s.x >= 0 && s.x < 8 && s.y >= 0 && s.y < 8
+}
+
+// String returns the square in algebraic notation, like "e4".
+func (s square) String() string {
+	return string([]byte{byte('a' + s.x), byte('1' + s.y)})
+}
+
+// position is a chess position, parsed from a FEN string
+type position struct {
+	// board contains the pieces, indexed by [x][y], see square
+	board [8][8]chessPiece
+	// whiteToMove is true if it is white's turn to move
+	whiteToMove bool
+	// castling contains the castling rights, like "KQkq", or "" if no side can castle
+	castling string
+	// enPassant is the square that a pawn can capture en passant, if hasEnPassant is true
+	enPassant    square
+	hasEnPassant bool
+	// fields is the number of fields in the FEN string, where 1 means that only the board is known
+	fields int
+}
+
+// newPosition parses a FEN string. Only the board section is required, if the side to
+// move is missing, it is white's turn, and no side can castle or capture en passant.
+func newPosition(fen string) (*position, error) {
+	if !validateFen(fen) {
+		return nil, fmt.Errorf("invalid fen: %v", fen)
+	}
+
+	p := &position{whiteToMove: true}
+	for rank, row := range strings.Split(normalizeFEN(fen), "/") {
+		for file, letter := range row {
+			p.board[file][invert(rank)] = letter2Piece[letter]
+		}
+	}
+
+	fields := strings.Fields(fen)
+	p.fields = len(fields)
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "w":
+		case "b":
+			p.whiteToMove = false
+		default:
+			return nil, fmt.Errorf("invalid side to move in fen: %v", fields[1])
+		}
+	}
+	if len(fields) > 2 && fields[2] != "-" {
+		if strings.Trim(fields[2], "KQkq") != "" {
+			return nil, fmt.Errorf("invalid castling rights in fen: %v", fields[2])
+		}
+		p.castling = fields[2]
+	}
+	if len(fields) > 3 && fields[3] != "-" {
+		a, err := newAlg(fields[3], false)
+		if err != nil || a.status != moveStatusNormal {
+			return nil, fmt.Errorf("invalid en passant square in fen: %v", fields[3])
+		}
+		p.enPassant, p.hasEnPassant = square{a.x, a.y}, true
+	}
+
+	return p, nil
+}
+
+// pieceAt returns the piece on a square.
+func (p *position) pieceAt(s square) chessPiece {
+	return p.board[s.x][s.y]
+}
+
+// king returns the square of the king of one side, or false if there is no king.
+func (p *position) king(white bool) (square, bool) {
+	for _, s := range p.pieces(white) {
+		if pieceKind(p.pieceAt(s)) == whiteKing {
+			return s, true
+		}
+	}
+
+	return square{}, false
+}
+
+// isWhite returns true if the piece is a white piece.
+func isWhite(p chessPiece) bool {
+	return p <= whiteKing
+}
+
+// pieceKind returns the white piece of the same kind, for example whiteRook for blackRook.
+func pieceKind(p chessPiece) chessPiece {
+	if p == noPiece {
+		return noPiece
+	}
+	return p % 6
+}
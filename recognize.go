@@ -0,0 +1,110 @@
+package chessImager
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+)
+
+// recognizeSymbols are the FEN symbols that Recognize tries to match
+// against every square. The space represents an empty square.
+const recognizeSymbols = " PBNRQKpbnrqk"
+
+// Recognize reads a position back from an image that has been rendered by
+// ChessImager using the given settings (white at the bottom). It returns the
+// board section of the FEN string.
+//
+// The board is located using the same geometry that is used when rendering,
+// and each square is then compared to templates of every piece (and of an
+// empty square) rendered with the same settings. The best matching template
+// wins, so highlights, annotations and moves on top of the board will make
+// the recognition less reliable.
+func Recognize(img image.Image, settings *Settings) (string, error) {
+	if img == nil {
+		return "", errors.New("image is nil")
+	}
+	if settings == nil {
+		return "", errors.New("settings is nil")
+	}
+
+	return (&Imager{settings: settings}).Recognize(img)
+}
+
+// Recognize reads a position back from an image that has been rendered
+// by this imager. See the package level Recognize function for details.
+func (i *Imager) Recognize(img image.Image) (string, error) {
+	size, err := i.getBoardSize()
+	if err != nil {
+		return "", err
+	}
+	if !img.Bounds().Size().Eq(size.Size()) {
+		return "", fmt.Errorf("image size %v does not match board size %v", img.Bounds().Size(), size.Size())
+	}
+
+	// The templates are rendered by a copy, so that the imager is not changed
+	c := *i
+	templates, err := c.getRecognizeTemplates()
+	if err != nil {
+		return "", err
+	}
+
+	ranks := make([]string, 8)
+	for y := 0; y < 8; y++ {
+		var sb strings.Builder
+		for x := 0; x < 8; x++ {
+			rect := i.getSquareBox(x, y).toImageRect()
+			best, bestDiff := ' ', -1
+			for s, template := range templates {
+				diff := compareSquare(img, template, rect)
+				if bestDiff < 0 || diff < bestDiff {
+					best, bestDiff = rune(recognizeSymbols[s]), diff
+				}
+			}
+			sb.WriteRune(best)
+		}
+		// Rank 8 comes first in a FEN string
+		ranks[invert(y)] = sb.String()
+	}
+
+	return compressFEN(strings.Join(ranks, "/")), nil
+}
+
+// getRecognizeTemplates renders one board for each symbol in recognizeSymbols,
+// where every square is occupied by that piece (or empty).
+func (i *Imager) getRecognizeTemplates() ([]image.Image, error) {
+	templates := make([]image.Image, len(recognizeSymbols))
+	for s, symbol := range recognizeSymbols {
+		rank := "8"
+		if symbol != ' ' {
+			rank = strings.Repeat(string(symbol), 8)
+		}
+		fen := strings.TrimSuffix(strings.Repeat(rank+"/", 8), "/")
+
+		img, err := i.Render(fen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render template for '%c' : %v", symbol, err)
+		}
+		templates[s] = img
+	}
+
+	return templates, nil
+}
+
+// compareSquare returns the sum of absolute differences of the color
+// channels between img and template, inside the rectangle rect
+// (relative to the top left corner of both images).
+func compareSquare(img, template image.Image, rect image.Rectangle) int {
+	ib, tb := img.Bounds().Min, template.Bounds().Min
+	diff := 0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r1, g1, b1, a1 := img.At(ib.X+x, ib.Y+y).RGBA()
+			r2, g2, b2, a2 := template.At(tb.X+x, tb.Y+y).RGBA()
+			diff += abs(int(r1>>8)-int(r2>>8)) + abs(int(g1>>8)-int(g2>>8)) +
+				abs(int(b1>>8)-int(b2>>8)) + abs(int(a1>>8)-int(a2>>8))
+		}
+	}
+
+	return diff
+}
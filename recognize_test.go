@@ -0,0 +1,75 @@
+package chessImager
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestRecognize(t *testing.T) {
+	t.Parallel()
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	imager := NewImager()
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	got, err := Recognize(img, imager.settings)
+	if err != nil {
+		t.Fatalf("failed to recognize : %v", err)
+	}
+	if want := strings.Split(fen, " ")[0]; got != want {
+		t.Errorf("Recognize() got = %v, want %v", got, want)
+	}
+}
+
+func TestRecognizeWithHighlight(t *testing.T) {
+	t.Parallel()
+
+	const fen = "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 2"
+	imager := NewImager()
+	ctx := imager.NewContext(fen).AddHighlight("e4").AddHighlight("e5")
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	got, err := imager.Recognize(img)
+	if err != nil {
+		t.Fatalf("failed to recognize : %v", err)
+	}
+	if want := strings.Split(fen, " ")[0]; got != want {
+		t.Errorf("Recognize() got = %v, want %v", got, want)
+	}
+}
+
+func TestRecognizeKeepsInverted(t *testing.T) {
+	t.Parallel()
+
+	const fen = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	imager := NewImager()
+	img, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	imager.inverted = true
+	if _, err = imager.Recognize(img); err != nil {
+		t.Fatalf("failed to recognize : %v", err)
+	}
+	if !imager.inverted {
+		t.Errorf("Recognize() changed the imager")
+	}
+}
+
+func TestRecognizeInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	_, err := NewImager().Recognize(img)
+	if err == nil {
+		t.Errorf("Recognize() of wrong sized image returned no error")
+	}
+}
@@ -1,6 +1,9 @@
 package chessImager
 
-import "image"
+import (
+	"image"
+	"math"
+)
 
 type Rectangle struct {
 	X      float64 `json:"x"`
@@ -34,3 +37,14 @@ func (r Rectangle) toImageRect() image.Rectangle {
 func (r Rectangle) center() (float64, float64) {
 	return r.X + r.Width/2, r.Y + r.Height/2
 }
+
+// clip returns the part of the rectangle that is inside c. If the rectangles do not
+// overlap, the result is an empty rectangle on the edge of c that is closest to r.
+func (r Rectangle) clip(c Rectangle) Rectangle {
+	x1 := math.Min(math.Max(r.X, c.X), c.X+c.Width)
+	y1 := math.Min(math.Max(r.Y, c.Y), c.Y+c.Height)
+	x2 := math.Max(math.Min(r.X+r.Width, c.X+c.Width), c.X)
+	y2 := math.Max(math.Min(r.Y+r.Height, c.Y+c.Height), c.Y)
+
+	return Rectangle{X: x1, Y: y1, Width: math.Max(0, x2-x1), Height: math.Max(0, y2-y1)}
+}
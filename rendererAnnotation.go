@@ -43,7 +43,7 @@ func (r *rendererAnnotation) drawAnnotationText(annotation Annotation, rect Rect
 		return err
 	}
 	if r.useInternalFont {
-		y -= 3 // SetFontFace/LoadFontFace problem : https://github.com/fogleman/gg/pull/76
+		y -= r.scaled(3) // SetFontFace/LoadFontFace problem : https://github.com/fogleman/gg/pull/76
 	}
 	r.gg.DrawStringAnchored(annotation.Text, x, y, 0.5, 0.5)
 
@@ -66,13 +66,30 @@ func (r *rendererAnnotation) getAnnotationRectangle(annotation Annotation) (Rect
 	if err != nil {
 		return Rectangle{}, err
 	}
+	if square.status != moveStatusNormal && square.status != moveStatusOffBoard {
+		return Rectangle{}, errors.New("invalid square for annotation : " + annotation.Square)
+	}
 
 	rect := r.getSquareBox(square.coords())
 	style := r.getStyle(annotation)
 	size := float64(style.Size)
-	const space = 2.0
+	space := r.scaled(2)
+	position := style.Position
+
+	if square.status == moveStatusOffBoard {
+		imageSize, err := r.getBoardSize()
+		if err != nil {
+			return Rectangle{}, err
+		}
+		rect = r.getAlgBox(square, imageSize.Dx(), imageSize.Dy())
+		if rect.Width == 0 || rect.Height == 0 {
+			return Rectangle{}, errors.New("there is no room outside the board for the annotation : " + annotation.Square)
+		}
+		// The area outside the board is usually narrow, so off-board annotations are centered
+		position = PositionTypeMiddle
+	}
 
-	switch style.Position {
+	switch position {
 	case PositionTypeTopLeft:
 		return Rectangle{
 			X:      rect.X + space,
@@ -117,6 +134,6 @@ func (r *rendererAnnotation) getStyle(annotation Annotation) *AnnotationStyle {
 	if annotation.Style == nil {
 		return &r.settings.AnnotationStyle
 	} else {
-		return annotation.Style
+		return annotation.Style.scale(r.getScale())
 	}
 }
@@ -2,9 +2,6 @@ package chessImager
 
 import (
 	"errors"
-	"fmt"
-	"image"
-	"os"
 
 	"github.com/fogleman/gg"
 )
@@ -18,7 +15,10 @@ type rendererBoard struct {
 func (r *rendererBoard) draw() error {
 	switch r.settings.Board.Type {
 	case boardTypeDefault:
-		r.drawDefault()
+		err := r.drawDefault()
+		if err != nil {
+			return err
+		}
 	case boardTypeImage:
 		err := r.drawImage()
 		if err != nil {
@@ -31,36 +31,55 @@ func (r *rendererBoard) draw() error {
 	return nil
 }
 
-func (r *rendererBoard) drawDefault() {
+func (r *rendererBoard) drawDefault() error {
 	board := r.getBoardBox()
+	def := r.settings.Board.Default
 
-	// Draw the entire board in the black color
-	r.gg.SetRGBA(r.settings.Board.Default.Black.toRGBA())
-	r.gg.DrawRectangle(board.coords())
-	r.gg.Fill()
-
-	// Draw the white squares, on top of the black board
-	r.gg.SetRGBA(r.settings.Board.Default.White.toRGBA())
+	var whites, blacks []Rectangle
 	for y := 0; y < 8; y++ {
 		for x := 0; x < 8; x++ {
 			if (y+x)%2 == 1 {
-				r.gg.DrawRectangle(r.getSquareBox(x, y).coords())
-				r.gg.Fill()
+				whites = append(whites, r.getSquareBox(x, y))
+			} else {
+				blacks = append(blacks, r.getSquareBox(x, y))
 			}
 		}
 	}
-}
 
-func (r *rendererBoard) drawImage() error {
-	f, err := os.Open(r.settings.Board.Image.Path)
-	if err != nil {
-		return fmt.Errorf("failed to load image : %v", err)
+	// Draw the entire board in the black color, unless each black
+	// square needs its own texture offset
+	if def.BlackFill == nil {
+		r.gg.SetRGBA(def.Black.toRGBA())
+		r.gg.DrawRectangle(board.coords())
+		r.gg.Fill()
+	} else {
+		rects := []Rectangle{board}
+		if def.BlackFill.Type == fillTypeTexture && def.BlackFill.RandomOffset {
+			rects = blacks
+		}
+		if err := r.fillRectangles(r.gg, def.BlackFill, board, rects); err != nil {
+			return err
+		}
+	}
+
+	// Draw the white squares, on top of the black board
+	if def.WhiteFill != nil {
+		return r.fillRectangles(r.gg, def.WhiteFill, board, whites)
+	}
+
+	r.gg.SetRGBA(def.White.toRGBA())
+	for _, square := range whites {
+		r.gg.DrawRectangle(square.coords())
+		r.gg.Fill()
 	}
-	defer f.Close()
 
-	img, _, err := image.Decode(f)
+	return nil
+}
+
+func (r *rendererBoard) drawImage() error {
+	img, err := r.loadBoardImage()
 	if err != nil {
-		return fmt.Errorf("failed to encode image : %v", err)
+		return err
 	}
 
 	r.gg.DrawImage(img, 0, 0)
@@ -13,6 +13,11 @@ func (r *rendererBorder) draw() error {
 		return nil
 	}
 
+	if r.settings.Border.Fill != nil {
+		area := Rectangle{Width: float64(r.gg.Width()), Height: float64(r.gg.Height())}
+		return r.fillRectangles(r.gg, r.settings.Border.Fill, area, []Rectangle{area})
+	}
+
 	// Set background color to border color
 	r.gg.SetRGBA(r.settings.Border.Color.toRGBA())
 	r.gg.Clear()
@@ -17,35 +17,77 @@ func (r *rendererHighlight) draw() error {
 		return nil
 	}
 
+	// Heatmaps are painted below the highlighted squares
+	if r.ctx.AttackHeatmap != nil {
+		err := r.drawAttackHeatmap()
+		if err != nil {
+			return err
+		}
+	}
+	if r.ctx.SquareValues != nil {
+		err := r.drawSquareValues()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.ctx.LastMove != nil {
+		err := r.drawLastMove()
+		if err != nil {
+			return err
+		}
+	}
+	if r.ctx.TacticalHints != nil {
+		err := r.drawHangingPieces()
+		if err != nil {
+			return err
+		}
+	}
+	if r.ctx.LegalMoves != nil {
+		err := r.drawLegalMoves()
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, high := range r.ctx.Highlight {
 		square, err := newAlg(high.Square, r.inverted)
 		if err != nil {
 			return err
 		}
-		b := r.getSquareBox(square.coords())
-
-		style := r.getStyle(high)
-		r.gg.SetRGBA(style.Color.toRGBA())
-
-		switch style.Type {
-		case HighlightTypeFull:
-			r.highlightFull(b)
-		case HighlightTypeBorder:
-			r.highlightBorder(b, style)
-		case HighlightTypeCircle:
-			r.highlightCircle(b, style)
-		case HighlightTypeFilledCircle:
-			r.highlightCircleFilled(b, style)
-		case HighlightTypeX:
-			r.highlightX(b, style)
-		default:
-			return errors.New("invalid highlight type")
+		b := r.getAlgBox(square, r.gg.Width(), r.gg.Height())
+
+		err = r.highlight(b, r.getStyle(high))
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// highlight highlights a square, or an off-board area, with a style.
+func (r *rendererHighlight) highlight(b Rectangle, style *HighlightStyle) error {
+	r.gg.SetRGBA(style.Color.toRGBA())
+
+	switch style.Type {
+	case HighlightTypeFull:
+		r.highlightFull(b)
+	case HighlightTypeBorder:
+		r.highlightBorder(b, style)
+	case HighlightTypeCircle:
+		r.highlightCircle(b, style)
+	case HighlightTypeFilledCircle:
+		r.highlightCircleFilled(b, style)
+	case HighlightTypeX:
+		r.highlightX(b, style)
+	default:
+		return errors.New("invalid highlight type")
+	}
+
+	return nil
+}
+
 func (r *rendererHighlight) highlightX(b Rectangle, style *HighlightStyle) {
 	bb := b.shrink(style.Factor)
 	x, y, w, h := bb.coords()
@@ -88,6 +130,6 @@ func (r *rendererHighlight) getStyle(high HighlightedSquare) *HighlightStyle {
 	if high.Style == nil {
 		return &r.settings.HighlightStyle
 	} else {
-		return high.Style
+		return high.Style.scale(r.getScale())
 	}
 }
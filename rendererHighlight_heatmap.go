@@ -0,0 +1,210 @@
+package chessImager
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// drawAttackHeatmap paints each square that is attacked, according to how many white pieces
+// attack it minus how many black pieces attack it.
+func (r *rendererHighlight) drawAttackHeatmap() error {
+	style := r.getHeatmapStyle(r.ctx.AttackHeatmap.Style)
+	pos, err := newPosition(r.ctx.Fen)
+	if err != nil {
+		return err
+	}
+
+	values := map[square]float64{}
+	limit := 1.0
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			s := square{x, y}
+			white, black := len(pos.attackers(s, true)), len(pos.attackers(s, false))
+			if white+black == 0 || (style.ContestedOnly && (white == 0 || black == 0)) {
+				continue
+			}
+			values[s] = float64(white - black)
+			limit = math.Max(limit, math.Abs(values[s]))
+		}
+	}
+
+	// The range is symmetric, so that equal control always gets the middle color, and the
+	// values are shown with a sign by default, since they are differences
+	return r.drawHeatmap(values, style, -limit, limit, "%+g")
+}
+
+// drawSquareValues paints the squares that have a value in the square values heatmap.
+func (r *rendererHighlight) drawSquareValues() error {
+	style := r.getHeatmapStyle(r.ctx.SquareValues.Style)
+
+	values := map[square]float64{}
+	low, high := math.Inf(1), math.Inf(-1)
+	for name, value := range r.ctx.SquareValues.Values {
+		a, err := newAlg(name, false)
+		if err != nil {
+			return err
+		}
+		if a.status != moveStatusNormal {
+			return fmt.Errorf("invalid square in square values : %q", name)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("invalid value in square values : %v for square %q", value, name)
+		}
+		values[square{a.x, a.y}] = value
+		low, high = math.Min(low, value), math.Max(high, value)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	return r.drawHeatmap(values, style, low, high, "%.4g")
+}
+
+// drawHeatmap paints the squares with the colors of their values, where low and high is the
+// range of the values, unless the style sets the range. It also draws the labels and the legend,
+// where the values are formatted with the label format of the style, or with format if it is not set.
+func (r *rendererHighlight) drawHeatmap(values map[square]float64, style *HeatmapStyle, low, high float64,
+	format string) error {
+	colors, err := style.getColormap()
+	if err != nil {
+		return err
+	}
+	if style.Min != nil {
+		low = *style.Min
+	}
+	if style.Max != nil {
+		high = *style.Max
+	}
+
+	// The squares are painted in order, so that the image does not depend on the map order
+	squares := make([]square, 0, len(values))
+	for s := range values {
+		squares = append(squares, s)
+	}
+	slices.SortFunc(squares, func(a, b square) int { return (a.x*8 + a.y) - (b.x*8 + b.y) })
+
+	for _, s := range squares {
+		t := 0.5 // All values are the same
+		if high > low {
+			t = (values[s] - low) / (high - low)
+		}
+		col := colormapAt(colors, t)
+		r.paintSquare(s, col, style.Opacity)
+		if style.Labels {
+			err = r.drawSquareLabel(s, r.formatHeatmapValue(style, format, values[s]), style, col)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if style.Legend {
+		return r.drawHeatmapLegend(style, colors, r.formatHeatmapValue(style, format, high),
+			r.formatHeatmapValue(style, format, low))
+	}
+
+	return nil
+}
+
+// paintSquare paints a square with a color, with the given opacity.
+func (r *rendererHighlight) paintSquare(s square, col color.RGBA, opacity float64) {
+	b := r.getBoardSquareBox(s)
+	c := ColorRGBA{RGBA: col}
+	red, green, blue, alpha := c.toRGBA()
+	r.gg.SetRGBA(red, green, blue, alpha*opacity)
+	r.highlightFull(b)
+}
+
+// drawSquareLabel draws the value of a square in the middle of the square, in black or white,
+// depending on which is more legible on the color of the square.
+func (r *rendererHighlight) drawSquareLabel(s square, text string, style *HeatmapStyle, col color.RGBA) error {
+	fontSize := style.LabelFontSize
+	if fontSize == 0 {
+		fontSize = r.settings.HeatmapStyle.LabelFontSize
+	}
+	err := r.setFontFace(r.gg, fontSize)
+	if err != nil {
+		return err
+	}
+
+	// Relative luminance, see https://www.w3.org/TR/WCAG20/#relativeluminancedef (without gamma)
+	luminance := (0.2126*float64(col.R) + 0.7152*float64(col.G) + 0.0722*float64(col.B)) / 255
+	if luminance > 0.5 {
+		r.gg.SetRGB(0, 0, 0)
+	} else {
+		r.gg.SetRGB(1, 1, 1)
+	}
+
+	x, y := r.getBoardSquareBox(s).center()
+	if r.useInternalFont {
+		y -= r.scaled(3) // SetFontFace/LoadFontFace problem : https://github.com/fogleman/gg/pull/76
+	}
+	r.gg.DrawStringAnchored(text, x, y, 0.5, 0.5)
+
+	return nil
+}
+
+// formatHeatmapValue formats a value with the label format of the style, the label format
+// of the settings, or with format if none of them are set.
+func (r *rendererHighlight) formatHeatmapValue(style *HeatmapStyle, format string, value float64) string {
+	switch {
+	case style.LabelFormat != "":
+		format = style.LabelFormat
+	case r.settings.HeatmapStyle.LabelFormat != "":
+		format = r.settings.HeatmapStyle.LabelFormat
+	}
+
+	return fmt.Sprintf(format, value)
+}
+
+// drawHeatmapLegend draws a vertical color bar in the border to the right of the board, with
+// the highest value above the bar and the lowest value below it. An error is returned if the
+// border is too narrow for the legend.
+func (r *rendererHighlight) drawHeatmapLegend(style *HeatmapStyle, colors []color.RGBA, high, low string) error {
+	board := r.getBoardBox()
+	x := board.X + board.Width
+	width := math.Min(board.X, float64(r.gg.Width())-x)
+	if width < r.scaled(4) {
+		return fmt.Errorf("the heatmap legend needs a border that is at least 4 pixels wide, got %v", width)
+	}
+
+	// The bar is painted one pixel row at a time, with the highest value at the top
+	barX, barWidth := x+width*0.3, width*0.4
+	for y := 0.0; y < board.Height; y++ {
+		c := ColorRGBA{RGBA: colormapAt(colors, 1-(y+0.5)/board.Height)}
+		r.gg.SetRGBA(c.toRGBA())
+		r.gg.DrawRectangle(barX, board.Y+y, barWidth, math.Min(1, board.Height-y))
+		r.gg.Fill()
+	}
+
+	fontSize := style.LegendFontSize
+	fontColor := style.LegendFontColor
+	if fontSize == 0 {
+		// Styles created with NewHeatmapStyle use the legend settings of the default heatmap style
+		fontSize, fontColor = r.settings.HeatmapStyle.LegendFontSize, r.settings.HeatmapStyle.LegendFontColor
+	}
+	err := r.setFontFace(r.gg, fontSize)
+	if err != nil {
+		return err
+	}
+
+	r.gg.SetRGBA(fontColor.toRGBA())
+	var dy float64
+	if r.useInternalFont {
+		dy = r.scaled(3) // SetFontFace/LoadFontFace problem : https://github.com/fogleman/gg/pull/76
+	}
+	r.gg.DrawStringAnchored(high, x+width/2, board.Y/2-dy, 0.5, 0.5)
+	r.gg.DrawStringAnchored(low, x+width/2, board.Y+board.Height+board.Y/2-dy, 0.5, 0.5)
+
+	return nil
+}
+
+// getHeatmapStyle returns the style of a heatmap, or the default style.
+func (r *rendererHighlight) getHeatmapStyle(style *HeatmapStyle) *HeatmapStyle {
+	if style == nil {
+		return &r.settings.HeatmapStyle
+	}
+	return style.scale(r.getScale())
+}
@@ -0,0 +1,50 @@
+package chessImager
+
+import (
+	"fmt"
+)
+
+// drawLegalMoves highlights the square of a piece, and the squares that it can move to.
+func (r *rendererHighlight) drawLegalMoves() error {
+	style := r.getLegalMovesStyle(r.ctx.LegalMoves.Style)
+	a, err := newAlg(r.ctx.LegalMoves.Square, false)
+	if err != nil {
+		return err
+	}
+	if a.status != moveStatusNormal {
+		return fmt.Errorf("invalid square for legal moves : %q", r.ctx.LegalMoves.Square)
+	}
+	pos, err := newPosition(r.ctx.Fen)
+	if err != nil {
+		return err
+	}
+	from := square{a.x, a.y}
+	if pos.pieceAt(from) == noPiece {
+		return fmt.Errorf("no piece on the square for legal moves : %q", r.ctx.LegalMoves.Square)
+	}
+
+	err = r.highlight(r.getBoardSquareBox(from), &style.SelectedStyle)
+	if err != nil {
+		return err
+	}
+	for _, to := range pos.legalMoves(from) {
+		s := &style.MoveStyle
+		if pos.pieceAt(to) != noPiece || pos.isEnPassant(from, to) {
+			s = &style.CaptureStyle
+		}
+		err = r.highlight(r.getBoardSquareBox(to), s)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLegalMovesStyle returns the style of the legal moves, or the default style.
+func (i *Imager) getLegalMovesStyle(style *LegalMovesStyle) *LegalMovesStyle {
+	if style == nil {
+		return &i.settings.LegalMovesStyle
+	}
+	return style.scale(i.getScale())
+}
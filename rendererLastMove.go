@@ -0,0 +1,63 @@
+package chessImager
+
+import (
+	"fmt"
+)
+
+// drawLastMove highlights the squares that the piece of the last move moved from and to.
+func (r *rendererHighlight) drawLastMove() error {
+	style := r.getLastMoveStyle(r.ctx.LastMove.Style)
+	from, to, err := r.ctx.lastMove()
+	if err != nil {
+		return err
+	}
+
+	err = r.highlight(r.getBoardSquareBox(from), &style.FromStyle)
+	if err != nil {
+		return err
+	}
+	return r.highlight(r.getBoardSquareBox(to), &style.ToStyle)
+}
+
+// getLastMoveArrow returns the arrow of the last move, if the arrow is turned on.
+func (r *rendererMoves) getLastMoveArrow() ([]layoutMove, error) {
+	if r.ctx.LastMove == nil {
+		return nil, nil
+	}
+	style := r.getLastMoveStyle(r.ctx.LastMove.Style)
+	if !style.Arrow {
+		return nil, nil
+	}
+	from, to, err := r.ctx.lastMove()
+	if err != nil {
+		return nil, err
+	}
+
+	return []layoutMove{{Move: Move{From: from.String(), To: to.String()}, style: &style.ArrowStyle}}, nil
+}
+
+// lastMove returns the last move, inferred from the previous position and the position of the context.
+func (c *ImageContext) lastMove() (from, to square, err error) {
+	before, err := newPosition(c.LastMove.PreviousFen)
+	if err != nil {
+		return from, to, err
+	}
+	after, err := newPosition(c.Fen)
+	if err != nil {
+		return from, to, err
+	}
+
+	from, to, err = inferMove(before, after)
+	if err != nil {
+		return from, to, fmt.Errorf("invalid last move : %w", err)
+	}
+	return from, to, nil
+}
+
+// getLastMoveStyle returns the style of the last move, or the default style.
+func (i *Imager) getLastMoveStyle(style *LastMoveStyle) *LastMoveStyle {
+	if style == nil {
+		return &i.settings.LastMoveStyle
+	}
+	return style.scale(i.getScale())
+}
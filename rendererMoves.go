@@ -17,25 +17,55 @@ func (r *rendererMoves) draw() error {
 		return nil
 	}
 
-	for _, move := range r.ctx.Moves {
+	// The last move and the tactical hints are rendered first, so that the moves of the context
+	// are rendered on top of them
+	lastMove, err := r.getLastMoveArrow()
+	if err != nil {
+		return err
+	}
+	tactics, err := r.getTacticsMoves()
+	if err != nil {
+		return err
+	}
+
+	hints := append(lastMove, tactics...)
+	moves := r.layoutStyledMoves(append(hints, r.styledMoves(r.ctx.Moves)...))
+	for _, move := range moves {
 		err := r.renderMove(move)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Labels are rendered last, so that no arrow covers them
+	for _, move := range moves {
+		if move.Label == "" {
+			continue
+		}
+		err := r.renderMoveLabel(move)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (r *rendererMoves) renderMove(move Move) error {
+func (r *rendererMoves) renderMove(move layoutMove) error {
 	var err error
-	style := r.getStyle(move)
+	style := move.style
 
 	switch style.Type {
 	case MoveTypeDots:
-		err = r.renderDottedMove(style, move)
+		err = r.renderDottedMove(style, move.Move)
 	case MoveTypeArrow:
-		err = r.renderArrowMove(style, move)
+		if isPathMove(style, move.Move) {
+			err = r.renderPathArrowMove(style, move.Move, move.offset)
+		} else if style.isShapedArrow() {
+			err = r.renderShapedArrowMove(style, move.Move, move.offset)
+		} else {
+			err = r.renderArrowMove(style, move.Move, move.offset)
+		}
 	default:
 		err = errors.New("illegal move type")
 	}
@@ -51,6 +81,6 @@ func (r *rendererMoves) getStyle(move Move) *MoveStyle {
 	if move.Style == nil {
 		return &r.settings.MoveStyle
 	} else {
-		return move.Style
+		return move.Style.scale(r.getScale())
 	}
 }
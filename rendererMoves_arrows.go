@@ -8,7 +8,7 @@ import (
 	"github.com/fogleman/gg"
 )
 
-func (r *rendererMoves) renderArrowMove(style *MoveStyle, move Move) error {
+func (r *rendererMoves) renderArrowMove(style *MoveStyle, move Move, offset float64) error {
 	r.gg.SetRGBA(style.Color.toRGBA())
 
 	from, err := newAlg(move.From, r.inverted)
@@ -35,7 +35,7 @@ func (r *rendererMoves) renderArrowMove(style *MoveStyle, move Move) error {
 	case from.status == moveStatusEmpty && to.status == moveStatusQueenSideCastling:
 		r.renderCastlingArrow(style, blackQueenSideCastling)
 	case from.status == moveStatusNormal && to.status == moveStatusNormal:
-		err = r.renderNormalMoveArrow(style, move, from, to)
+		err = r.renderNormalMoveArrow(style, move, from, to, offset)
 		if err != nil {
 			return err
 		}
@@ -44,7 +44,7 @@ func (r *rendererMoves) renderArrowMove(style *MoveStyle, move Move) error {
 	return nil
 }
 
-func (r *rendererMoves) renderNormalMoveArrow(style *MoveStyle, move Move, from alg, to alg) error {
+func (r *rendererMoves) renderNormalMoveArrow(style *MoveStyle, move Move, from alg, to alg, offset float64) error {
 	fromX, fromY := from.coords()
 	toX, toY := to.coords()
 	dx, dy := toX-fromX, toY-fromY
@@ -69,14 +69,14 @@ func (r *rendererMoves) renderNormalMoveArrow(style *MoveStyle, move Move, from
 			factor = math.Sqrt(2)
 		}
 		length += rect.Width/2*factor - styleBox.Width
-		r.renderArrow(length, styleBox.Width, fx, fy, 0, dir)
+		r.renderArrow(length, styleBox.Width, fx, fy, offset, dir)
 	} else {
 		// Knight type move (or other weird illegal move)
 		dir, rl := r.getKnightDirection(dx, dy)
 		if rl == right {
-			r.renderKnightArrowRight(rect.Width, styleBox.Width, fx, fy, dir)
+			r.renderKnightArrowRight(rect.Width, styleBox.Width, fx, fy, offset, dir)
 		} else {
-			r.renderKnightArrowLeft(rect.Width, styleBox.Width, fx, fy, dir)
+			r.renderKnightArrowLeft(rect.Width, styleBox.Width, fx, fy, offset, dir)
 		}
 	}
 	return nil
@@ -134,38 +134,42 @@ func (r *rendererMoves) renderArrow(length, width, fx, fy, dy float64, dir direc
 	r.gg.RotateAbout(gg.Radians(float64(-dir)), fx, fy)
 }
 
-func (r *rendererMoves) renderKnightArrowRight(square, width, fx, fy float64, dir direction) {
+func (r *rendererMoves) renderKnightArrowRight(square, width, fx, fy, offset float64, dir direction) {
 	length := square * 2
 
 	r.gg.RotateAbout(gg.Radians(float64(dir)), fx, fy)
-	r.gg.MoveTo(fx-width/2, fy)
-	r.gg.LineTo(fx-width/2, fy-length-width/2)
-	r.gg.LineTo(fx+square/2-width, fy-length-width/2)
-	r.gg.LineTo(fx+square/2-width, fy-length-width)
-	r.gg.LineTo(fx+square/2, fy-length)
-	r.gg.LineTo(fx+square/2-width, fy-length+width)
-	r.gg.LineTo(fx+square/2-width, fy-length+width/2)
-	r.gg.LineTo(fx+width/2, fy-length+width/2)
-	r.gg.LineTo(fx+width/2, fy)
-	r.gg.LineTo(fx-width/2, fy)
+	// Overlapping arrows are moved to the right, as seen in the direction of the head
+	y := fy + offset
+	r.gg.MoveTo(fx-width/2, y)
+	r.gg.LineTo(fx-width/2, y-length-width/2)
+	r.gg.LineTo(fx+square/2-width, y-length-width/2)
+	r.gg.LineTo(fx+square/2-width, y-length-width)
+	r.gg.LineTo(fx+square/2, y-length)
+	r.gg.LineTo(fx+square/2-width, y-length+width)
+	r.gg.LineTo(fx+square/2-width, y-length+width/2)
+	r.gg.LineTo(fx+width/2, y-length+width/2)
+	r.gg.LineTo(fx+width/2, y)
+	r.gg.LineTo(fx-width/2, y)
 	r.gg.Fill()
 	r.gg.RotateAbout(gg.Radians(float64(-dir)), fx, fy)
 }
 
-func (r *rendererMoves) renderKnightArrowLeft(square, width, fx, fy float64, dir direction) {
+func (r *rendererMoves) renderKnightArrowLeft(square, width, fx, fy, offset float64, dir direction) {
 	length := square * 2
 
 	r.gg.RotateAbout(gg.Radians(float64(dir)), fx, fy)
-	r.gg.MoveTo(fx-width/2, fy)
-	r.gg.LineTo(fx-width/2, fy-length+width/2)
-	r.gg.LineTo(fx-square/2+width, fy-length+width/2)
-	r.gg.LineTo(fx-square/2+width, fy-length+width)
-	r.gg.LineTo(fx-square/2, fy-length)
-	r.gg.LineTo(fx-square/2+width, fy-length-width)
-	r.gg.LineTo(fx-square/2+width, fy-length-width/2)
-	r.gg.LineTo(fx+width/2, fy-length-width/2)
-	r.gg.LineTo(fx+width/2, fy)
-	r.gg.LineTo(fx-width/2, fy)
+	// Overlapping arrows are moved to the right, as seen in the direction of the head
+	y := fy - offset
+	r.gg.MoveTo(fx-width/2, y)
+	r.gg.LineTo(fx-width/2, y-length+width/2)
+	r.gg.LineTo(fx-square/2+width, y-length+width/2)
+	r.gg.LineTo(fx-square/2+width, y-length+width)
+	r.gg.LineTo(fx-square/2, y-length)
+	r.gg.LineTo(fx-square/2+width, y-length-width)
+	r.gg.LineTo(fx-square/2+width, y-length-width/2)
+	r.gg.LineTo(fx+width/2, y-length-width/2)
+	r.gg.LineTo(fx+width/2, y)
+	r.gg.LineTo(fx-width/2, y)
 	r.gg.Fill()
 	r.gg.RotateAbout(gg.Radians(float64(-dir)), fx, fy)
 }
@@ -210,17 +214,19 @@ func (r *rendererMoves) getNextToLast(move Move) (Rectangle, error) {
 		return Rectangle{}, err
 	}
 
-	dx, dy := to.x-from.x, to.y-from.y
+	fromX, fromY := from.coords()
+	toX, toY := to.coords()
+	dx, dy := toX-fromX, toY-fromY
 
 	switch {
 	case dx == 0 && dy == 0:
 		return Rectangle{}, errors.New("no move") // Ignore no move
 	case dx == 0 || dy == 0 || abs(dx) == abs(dy): // Straight moves
-		return r.getSquareBox(to.x-sgn(dx), to.y-sgn(dy)), nil
+		return r.getSquareBox(toX-sgn(dx), toY-sgn(dy)), nil
 	case abs(dx) == 1 && abs(dy) == 2: // Knight move 1
-		return r.getSquareBox(to.x-sgn(dx), to.y), nil
+		return r.getSquareBox(toX-sgn(dx), toY), nil
 	case abs(dx) == 2 && abs(dy) == 1: // Knight move 2
-		return r.getSquareBox(to.x, to.y-sgn(dy)), nil
+		return r.getSquareBox(toX, toY-sgn(dy)), nil
 	default:
 		panic("illegal move")
 	}
@@ -8,6 +8,10 @@ import (
 func (r *rendererMoves) renderDottedMove(style *MoveStyle, move Move) error {
 	r.gg.SetRGBA(style.Color.toRGBA())
 
+	if len(move.Via) > 0 || isOffBoard(move.From) || isOffBoard(move.To) {
+		return r.renderDottedPath(style, move)
+	}
+
 	from, err := newAlg(move.From, r.inverted)
 	if err != nil {
 		return err
@@ -70,6 +74,24 @@ func (r *rendererMoves) renderNormalDottedMove(style *MoveStyle, from, to alg) e
 	return nil
 }
 
+// renderDottedPath renders a dotted move that passes through the Via squares,
+// one part at a time. No dots are rendered on off-board positions.
+func (r *rendererMoves) renderDottedPath(style *MoveStyle, move Move) error {
+	squares, err := r.getMoveSquares(move)
+	if err != nil {
+		return err
+	}
+
+	for n := 1; n < len(squares); n++ {
+		err = r.renderNormalDottedMove(style, squares[n-1], squares[n])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *rendererMoves) renderCastlingDottedLine(castling castlingStatus, style *MoveStyle) {
 	var kx, ky, rx, ry int
 	var dx, dy, rookMoves = 1, 0, 3
@@ -111,6 +133,9 @@ func (r *rendererMoves) renderDottedLine(x, y *int, dx, dy, moves int, cdy float
 }
 
 func (r *rendererMoves) renderDotInSquare(x, y int, cdy float64, style *MoveStyle) {
+	if x < 0 || x > 7 || y < 0 || y > 7 {
+		return // Off-board position
+	}
 	bb := r.getSquareBox(x, y).shrink(style.Factor)
 	cX, cY := bb.center()
 	r.gg.DrawCircle(cX, cY+cdy, bb.Width/2)
@@ -0,0 +1,155 @@
+package chessImager
+
+import (
+	"math"
+)
+
+// weighted returns a copy of the style, where the width and the opacity of the
+// move has been reduced according to the weight (0-1) of the move.
+func (s *MoveStyle) weighted(weight float64) *MoveStyle {
+	weight = math.Max(0, math.Min(1, weight))
+	c := *s
+	c.Factor = s.Factor * (1 - s.WeightWidth*(1-weight))
+	opacity := 1 - s.WeightOpacity*(1-weight)
+	c.Color.A = uint8(math.Round(float64(s.Color.A) * opacity))
+	c.Color2.A = uint8(math.Round(float64(s.Color2.A) * opacity))
+
+	return &c
+}
+
+// renderMoveLabel renders the label of a move, on the middle of the move or near its head.
+func (r *rendererMoves) renderMoveLabel(move layoutMove) error {
+	style := move.style
+	line, headLength, err := r.getMoveLine(move)
+	if err != nil || line == nil {
+		return err
+	}
+
+	fontSize := style.LabelFontSize
+	if fontSize == 0 {
+		// Styles created with NewMoveStyle use the label settings of the default move style
+		fontSize = r.settings.MoveStyle.LabelFontSize
+	}
+	fontColor, backgroundColor := style.LabelColor, style.LabelBackgroundColor
+	if fontColor.A == 0 && backgroundColor.A == 0 {
+		fontColor, backgroundColor = r.settings.MoveStyle.LabelColor, r.settings.MoveStyle.LabelBackgroundColor
+	}
+
+	err = r.setFontFace(r.gg, fontSize)
+	if err != nil {
+		return err
+	}
+	textWidth, _ := r.gg.MeasureString(move.Label)
+	height := float64(fontSize) * 1.4
+	width := math.Max(textWidth+float64(fontSize)*0.6, height)
+
+	length := pathLength(line)
+	distance := length / 2
+	if style.LabelPosition == MoveLabelPositionHead {
+		distance = math.Max(0, length-headLength-width/2)
+	}
+	cut := cutPath(line, distance)
+	p := cut[len(cut)-1]
+
+	r.gg.SetRGBA(backgroundColor.toRGBA())
+	r.gg.DrawRoundedRectangle(p.x-width/2, p.y-height/2, width, height, height/2)
+	r.gg.Fill()
+
+	r.gg.SetRGBA(fontColor.toRGBA())
+	y := p.y
+	if r.useInternalFont {
+		y -= r.scaled(3) // SetFontFace/LoadFontFace problem : https://github.com/fogleman/gg/pull/76
+	}
+	r.gg.DrawStringAnchored(move.Label, p.x, y, 0.5, 0.5)
+
+	return nil
+}
+
+// getMoveLine returns the center line of a move, and the length of the arrow head. The
+// line of a castling move is the line of the king.
+func (r *rendererMoves) getMoveLine(move layoutMove) ([]point, float64, error) {
+	style := move.style
+	headLength := r.getSquareBox(0, 0).shrink(style.Factor).Width * style.headLength()
+
+	// Path moves, and all moves that start or end off the board, use the line of the path arrow
+	if isOffBoard(move.From) || isOffBoard(move.To) || (style.Type == MoveTypeArrow && isPathMove(style, move.Move)) {
+		if style.Type == MoveTypeDots {
+			headLength = 0
+		}
+		line, err := r.getArrowPath(style, move.Move)
+		return offsetPath(line, move.offset), headLength, err
+	}
+
+	from, err := newAlg(move.From, r.inverted)
+	if err != nil {
+		return nil, 0, err
+	}
+	to, err := newAlg(move.To, r.inverted)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var squares []alg
+	castling := false
+	switch {
+	case from.status == moveStatusNormal && to.status == moveStatusNormal:
+		if len(move.Via) > 0 {
+			squares, err = r.getMoveSquares(move.Move)
+			if err != nil {
+				return nil, 0, err
+			}
+		} else {
+			squares = []alg{from, to}
+		}
+	case from.status == moveStatusKingSideCastling || to.status == moveStatusKingSideCastling ||
+		from.status == moveStatusQueenSideCastling || to.status == moveStatusQueenSideCastling:
+		squares, err = r.getCastlingKingSquares(from, to)
+		if err != nil {
+			return nil, 0, err
+		}
+		castling = true
+	default:
+		return nil, 0, nil
+	}
+
+	var line []point
+	for _, square := range squares {
+		x, y := r.getSquareBox(square.coords()).center()
+		line = append(line, point{x, y})
+	}
+	if line[0] == line[len(line)-1] && len(line) == 2 {
+		return nil, 0, nil // Ignore no move
+	}
+	if style.Type == MoveTypeDots || castling {
+		return line, 0, nil
+	}
+
+	// Straight and knight arrows
+	return r.getArrowLine(from, to, move.offset), headLength, nil
+}
+
+// getCastlingKingSquares returns the squares that the king moves between when castling.
+func (r *rendererMoves) getCastlingKingSquares(from, to alg) ([]alg, error) {
+	var king, target string
+	switch {
+	case from.status == moveStatusKingSideCastling:
+		king, target = "e1", "g1"
+	case from.status == moveStatusQueenSideCastling:
+		king, target = "e1", "c1"
+	case to.status == moveStatusKingSideCastling:
+		king, target = "e8", "g8"
+	default:
+		king, target = "e8", "c8"
+	}
+
+	kingSquare, err := newAlg(king, r.inverted)
+	if err != nil {
+		return nil, err
+	}
+	targetSquare, err := newAlg(target, r.inverted)
+	if err != nil {
+		return nil, err
+	}
+
+	return []alg{kingSquare, targetSquare}, nil
+}
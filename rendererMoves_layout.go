@@ -0,0 +1,242 @@
+package chessImager
+
+import (
+	"reflect"
+	"slices"
+)
+
+// laneSpacing is the distance between the centers of two overlapping arrows, relative to the arrow width
+const laneSpacing = 1.5
+
+// sameDirectionLimit is the smallest cosine of the angle between two arrows, that end in the same
+// place, for the arrows to overlap (about 25 degrees)
+const sameDirectionLimit = 0.9
+
+// layoutMove is a move, and how far it has been moved sideways by the layout
+type layoutMove struct {
+	Move
+	style *MoveStyle
+	// offset is the sideways offset in pixels, to the right as seen in the direction of the move
+	offset float64
+}
+
+// straightArrow is a straight arrow move, as a line of squares
+type straightArrow struct {
+	from, to alg
+	dx, dy   int // The direction, one square at a time
+	length   int // The number of squares from the from square to the to square
+}
+
+// arrowEnd is where an arrow enters its target square, and in which direction
+type arrowEnd struct {
+	x, y int   // The target square
+	tip  point // The tip of the arrow
+	dir  point // The direction of the last part of the arrow
+}
+
+// layoutArrow is an arrow move in the layout, that ends on the board
+type layoutArrow struct {
+	index      int     // The index of the move in the layout
+	width      float64 // The width of the arrow
+	end        arrowEnd
+	straight   straightArrow
+	isStraight bool
+}
+
+// layoutMoves merges duplicate moves and moves arrows that overlap each other sideways, so
+// that they are all visible. Arrows overlap if they are straight arrows on the same line, or
+// if they enter the same square in the same place, like a rook arrow and a knight arrow that
+// both enter e4 from the left. Moves that overlap are placed in lanes, in the order they were
+// added, so the result does not depend on anything but the moves.
+func (r *rendererMoves) layoutMoves(moves []Move) []layoutMove {
+	return r.layoutStyledMoves(r.styledMoves(moves))
+}
+
+// styledMoves returns the moves, with their styles.
+func (r *rendererMoves) styledMoves(moves []Move) []layoutMove {
+	styled := make([]layoutMove, len(moves))
+	for n, move := range moves {
+		styled[n] = layoutMove{Move: move, style: r.getStyle(move)}
+	}
+
+	return styled
+}
+
+// layoutStyledMoves is layoutMoves, for moves where the style has already been resolved.
+func (r *rendererMoves) layoutStyledMoves(moves []layoutMove) []layoutMove {
+	var result []layoutMove
+	for _, m := range moves {
+		move, style := m.Move, m.style
+		if move.Weight != nil {
+			style = style.weighted(*move.Weight)
+		}
+		if !slices.ContainsFunc(result, func(m layoutMove) bool { return isSameMove(m, move, style) }) {
+			result = append(result, layoutMove{Move: move, style: style})
+		}
+	}
+
+	square := r.getSquareBox(0, 0)
+	var arrows []layoutArrow
+	for n, m := range result {
+		end, ok := r.getArrowEnd(m)
+		if !ok {
+			continue
+		}
+		arrow := layoutArrow{index: n, width: square.shrink(m.style.Factor).Width, end: end}
+		arrow.straight, arrow.isStraight = r.getStraightArrow(m)
+		arrows = append(arrows, arrow)
+	}
+
+	// Group arrows that overlap, directly or through other arrows
+	group := make([]int, len(arrows))
+	for n := range group {
+		group[n] = n
+	}
+	var find func(n int) int
+	find = func(n int) int {
+		if group[n] != n {
+			group[n] = find(group[n])
+		}
+		return group[n]
+	}
+	for a := range arrows {
+		for b := a + 1; b < len(arrows); b++ {
+			if arrows[a].overlaps(arrows[b]) {
+				// The root is always the first arrow in the group
+				ra, rb := find(a), find(b)
+				group[max(ra, rb)] = min(ra, rb)
+			}
+		}
+	}
+
+	for root := range arrows {
+		var lanes []int
+		for n := range arrows {
+			if find(n) == root {
+				lanes = append(lanes, n)
+			}
+		}
+		if len(lanes) < 2 {
+			continue
+		}
+
+		var width float64
+		for _, n := range lanes {
+			width = max(width, arrows[n].width)
+		}
+		// Keep all the lanes within the squares
+		spacing := min(width*laneSpacing, square.Width/float64(len(lanes)))
+
+		for lane, n := range lanes {
+			offset := (float64(lane) - float64(len(lanes)-1)/2) * spacing
+			// The lanes are counted from the right side of the first arrow in the group,
+			// so arrows in the opposite direction are moved the other way.
+			if arrows[n].end.dir.dot(arrows[root].end.dir) < 0 {
+				offset = -offset
+			}
+			result[arrows[n].index].offset = offset
+		}
+	}
+
+	return result
+}
+
+// isSameMove returns true if the moves are exact duplicates.
+func isSameMove(m layoutMove, move Move, style *MoveStyle) bool {
+	return m.From == move.From && m.To == move.To && slices.Equal(m.Via, move.Via) && m.Label == move.Label &&
+		reflect.DeepEqual(m.style, style)
+}
+
+// getStraightArrow returns the line of squares of a move, if the move is rendered as a straight arrow.
+func (r *rendererMoves) getStraightArrow(m layoutMove) (straightArrow, bool) {
+	if m.style.Type != MoveTypeArrow || isPathMove(m.style, m.Move) {
+		return straightArrow{}, false
+	}
+
+	from, err := newAlg(m.From, r.inverted)
+	if err != nil || from.status != moveStatusNormal {
+		return straightArrow{}, false
+	}
+	to, err := newAlg(m.To, r.inverted)
+	if err != nil || to.status != moveStatusNormal {
+		return straightArrow{}, false
+	}
+
+	dx, dy := to.x-from.x, to.y-from.y
+	if (dx == 0 && dy == 0) || (dx != 0 && dy != 0 && abs(dx) != abs(dy)) {
+		return straightArrow{}, false // No move, or a knight type move
+	}
+
+	return straightArrow{from: from, to: to, dx: sgn(dx), dy: sgn(dy), length: max(abs(dx), abs(dy))}, true
+}
+
+// getArrowEnd returns where an arrow move enters its target square, if the target square
+// is on the board. Path arrows need the image context, since they can start off the board.
+func (r *rendererMoves) getArrowEnd(m layoutMove) (arrowEnd, bool) {
+	if m.style.Type != MoveTypeArrow {
+		return arrowEnd{}, false
+	}
+	to, err := newAlg(m.To, r.inverted)
+	if err != nil || to.status != moveStatusNormal {
+		return arrowEnd{}, false
+	}
+
+	var line []point
+	if isPathMove(m.style, m.Move) {
+		line, err = r.getArrowPath(m.style, m.Move)
+		if err != nil {
+			return arrowEnd{}, false
+		}
+	} else {
+		from, err := newAlg(m.From, r.inverted)
+		if err != nil || from.status != moveStatusNormal {
+			return arrowEnd{}, false
+		}
+		line = r.getArrowLine(from, to, 0)
+	}
+	if len(line) < 2 {
+		return arrowEnd{}, false // No move
+	}
+
+	tip := line[len(line)-1]
+	return arrowEnd{x: to.x, y: to.y, tip: tip, dir: tip.sub(line[len(line)-2]).normalize()}, true
+}
+
+// overlaps returns true if the arrows are straight arrows that overlap on the same line,
+// or if they end in the same place, in almost the same direction.
+func (a layoutArrow) overlaps(b layoutArrow) bool {
+	if a.isStraight && b.isStraight && a.straight.overlaps(b.straight) {
+		return true
+	}
+
+	return a.end.x == b.end.x && a.end.y == b.end.y && a.end.tip.sub(b.end.tip).length() < max(a.width, b.width) &&
+		a.end.dir.dot(b.end.dir) > sameDirectionLimit
+}
+
+// overlaps returns true if the arrows are on the same line, and share at least one part
+// of that line. Arrows that only touch each other, like a1-a4 and a4-a8, do not overlap.
+func (a straightArrow) overlaps(b straightArrow) bool {
+	// The arrows must be parallel...
+	if !(a.dx == b.dx && a.dy == b.dy) && !(a.dx == -b.dx && a.dy == -b.dy) {
+		return false
+	}
+	// ...and on the same line
+	fx, fy := b.from.x-a.from.x, b.from.y-a.from.y
+	if fx*a.dy != fy*a.dx {
+		return false
+	}
+
+	// The positions of b along a, in squares
+	pos := func(x, y int) int {
+		if a.dx != 0 {
+			return (x - a.from.x) * a.dx
+		}
+		return (y - a.from.y) * a.dy
+	}
+	start, end := pos(b.from.x, b.from.y), pos(b.to.x, b.to.y)
+	if start > end {
+		start, end = end, start
+	}
+
+	return min(end, a.length)-max(start, 0) > 0
+}
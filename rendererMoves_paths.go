@@ -0,0 +1,201 @@
+package chessImager
+
+import (
+	"fmt"
+	"math"
+)
+
+// curveSegments is the number of line segments that a curved part of an arrow is drawn with
+const curveSegments = 32
+
+// point is a position in the image
+type point struct {
+	x, y float64
+}
+
+func (p point) add(q point) point             { return point{p.x + q.x, p.y + q.y} }
+func (p point) sub(q point) point             { return point{p.x - q.x, p.y - q.y} }
+func (p point) mul(f float64) point           { return point{p.x * f, p.y * f} }
+func (p point) length() float64               { return math.Hypot(p.x, p.y) }
+func (p point) lerp(q point, t float64) point { return p.add(q.sub(p).mul(t)) }
+func (p point) dot(q point) float64           { return p.x*q.x + p.y*q.y }
+
+// normalize returns the unit vector in the direction of p
+func (p point) normalize() point {
+	l := p.length()
+	if l == 0 {
+		return point{}
+	}
+	return p.mul(1 / l)
+}
+
+// isPathMove returns true if the move must be rendered with the path renderer,
+// since the legacy arrows can only be straight, or shaped like knight moves,
+// and must start and end on the board.
+func isPathMove(style *MoveStyle, move Move) bool {
+	return style.Curve != 0 || len(move.Via) > 0 || isOffBoard(move.From) || isOffBoard(move.To)
+}
+
+// getMoveSquares returns all the squares of a move, from the first to the last square.
+func (r *rendererMoves) getMoveSquares(move Move) ([]alg, error) {
+	names := append(append([]string{move.From}, move.Via...), move.To)
+	squares := make([]alg, len(names))
+	for n, name := range names {
+		square, err := newAlg(name, r.inverted)
+		if err != nil {
+			return nil, err
+		}
+		if square.status != moveStatusNormal && square.status != moveStatusOffBoard {
+			return nil, fmt.Errorf("invalid square in move path : %q", name)
+		}
+		squares[n] = square
+	}
+
+	return squares, nil
+}
+
+// renderPathArrowMove renders an arrow that follows the squares of a move, where
+// each part of the arrow is bent according to MoveStyle.Curve, moved sideways by offset.
+func (r *rendererMoves) renderPathArrowMove(style *MoveStyle, move Move, offset float64) error {
+	path, err := r.getArrowPath(style, move)
+	if err != nil || path == nil {
+		return err
+	}
+	path = offsetPath(path, offset)
+
+	square := r.getSquareBox(0, 0)
+	width := square.shrink(style.Factor).Width
+	r.renderPathArrow(style, style.Color, path, width)
+
+	return nil
+}
+
+// getArrowPath returns the center line of a curved or multi-segment arrow, from the center
+// of the first square to the tip of the arrow. Off-board positions use the center of the
+// area next to the board. A move that does not move returns nil.
+func (r *rendererMoves) getArrowPath(style *MoveStyle, move Move) ([]point, error) {
+	squares, err := r.getMoveSquares(move)
+	if err != nil {
+		return nil, err
+	}
+
+	centers := make([]point, 0, len(squares))
+	for _, square := range squares {
+		x, y := r.getAlgBox(square, r.gg.Width(), r.gg.Height()).center()
+		// Skip repeated squares, they would give zero length parts
+		if len(centers) == 0 || centers[len(centers)-1] != (point{x, y}) {
+			centers = append(centers, point{x, y})
+		}
+	}
+	if len(centers) < 2 {
+		return nil, nil // Ignore no move
+	}
+
+	path := []point{centers[0]}
+	for n := 1; n < len(centers); n++ {
+		path = append(path, curve(centers[n-1], centers[n], style.Curve)...)
+	}
+
+	// The arrow ends where it enters the last square, or at the last off-board position
+	last := squares[len(squares)-1]
+	if last.status == moveStatusOffBoard {
+		return path, nil
+	}
+	return clipPathAtRect(path, r.getSquareBox(last.coords())), nil
+}
+
+// offsetPath returns the path moved sideways by offset, to the right as seen in the direction
+// of the last part of the path, so that arrows that end in the same place are moved apart.
+func offsetPath(path []point, offset float64) []point {
+	if offset == 0 || len(path) < 2 {
+		return path
+	}
+
+	dir := path[len(path)-1].sub(path[len(path)-2]).normalize()
+	side := point{-dir.y, dir.x}.mul(offset)
+	result := make([]point, len(path))
+	for n, p := range path {
+		result[n] = p.add(side)
+	}
+
+	return result
+}
+
+// curve returns the points of a quadratic bezier curve from p0 to p1 (p0 not
+// included), that bends sideways by bend times the distance between the points.
+func curve(p0, p1 point, bend float64) []point {
+	if bend == 0 {
+		return []point{p1}
+	}
+
+	d := p1.sub(p0)
+	// The left side of the direction, since the y-axis points down
+	normal := point{d.y, -d.x}
+	control := p0.lerp(p1, 0.5).add(normal.mul(bend))
+
+	points := make([]point, 0, curveSegments)
+	for n := 1; n <= curveSegments; n++ {
+		t := float64(n) / curveSegments
+		points = append(points, p0.lerp(control, t).lerp(control.lerp(p1, t), t))
+	}
+
+	return points
+}
+
+// clipPathAtRect returns the part of the path that ends where the path enters the
+// rectangle for the last time. If the path starts inside the rectangle, and never
+// leaves it, the path is returned as it is.
+func clipPathAtRect(path []point, rect Rectangle) []point {
+	inside := func(p point) bool {
+		return p.x >= rect.X && p.x <= rect.X+rect.Width && p.y >= rect.Y && p.y <= rect.Y+rect.Height
+	}
+
+	for n := len(path) - 2; n >= 0; n-- {
+		if inside(path[n]) {
+			continue
+		}
+
+		// Binary search for the point where the segment enters the rectangle
+		out, in := path[n], path[n+1]
+		for i := 0; i < 32; i++ {
+			mid := out.lerp(in, 0.5)
+			if inside(mid) {
+				in = mid
+			} else {
+				out = mid
+			}
+		}
+
+		return append(path[:n+1:n+1], in)
+	}
+
+	return path
+}
+
+// pathLength returns the length of a path.
+func pathLength(path []point) float64 {
+	var length float64
+	for n := 1; n < len(path); n++ {
+		length += path[n].sub(path[n-1]).length()
+	}
+
+	return length
+}
+
+// cutPath returns the first part of the path, up to the distance length along the path.
+func cutPath(path []point, length float64) []point {
+	result := []point{path[0]}
+	for n := 1; n < len(path); n++ {
+		l := path[n].sub(path[n-1]).length()
+		if l >= length {
+			if l > 0 {
+				result = append(result, path[n-1].lerp(path[n], length/l))
+			}
+			return result
+		}
+		length -= l
+		result = append(result, path[n])
+	}
+
+	return result
+}
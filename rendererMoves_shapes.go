@@ -0,0 +1,351 @@
+package chessImager
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"slices"
+
+	"github.com/fogleman/gg"
+)
+
+// isShapedArrow returns true if the style uses any of the arrow shape options, that
+// the legacy arrows do not support (outline, dashes, head options and round tails).
+func (s *MoveStyle) isShapedArrow() bool {
+	return s.Outline != nil || len(s.Dash) > 0 || s.HeadLength != 0 || s.HeadWidth != 0 ||
+		s.HeadType != MoveHeadTypeFilled || s.DoubleHead || s.RoundTail
+}
+
+// headLength returns the length of the arrow head, relative to the arrow width.
+func (s *MoveStyle) headLength() float64 {
+	if s.HeadLength == 0 {
+		return 1
+	}
+	return s.HeadLength
+}
+
+// headWidth returns the width of the arrow head, relative to the arrow width.
+func (s *MoveStyle) headWidth() float64 {
+	if s.HeadWidth == 0 {
+		return 2
+	}
+	return s.HeadWidth
+}
+
+// renderShapedArrowMove renders a straight, knight or castling arrow, with the same
+// geometry as the legacy arrows, but using the shape options of the style.
+func (r *rendererMoves) renderShapedArrowMove(style *MoveStyle, move Move, offset float64) error {
+	from, err := newAlg(move.From, r.inverted)
+	if err != nil {
+		return err
+	}
+	to, err := newAlg(move.To, r.inverted)
+	if err != nil {
+		return err
+	}
+
+	width := r.getSquareBox(0, 0).shrink(style.Factor).Width
+
+	switch {
+	case from.status == moveStatusIllegal:
+		return errors.New(fmt.Sprintf("illegal from move : %s", from))
+	case to.status == moveStatusIllegal:
+		return errors.New(fmt.Sprintf("illegal to move : %s", to))
+	case from.status == moveStatusNormal && to.status == moveStatusNormal:
+		line := r.getArrowLine(from, to, offset)
+		if line != nil {
+			r.renderPathArrow(style, style.Color, line, width)
+		}
+	case from.status == moveStatusKingSideCastling && to.status == moveStatusEmpty:
+		r.renderShapedCastlingArrow(style, whiteKingSideCastling, width)
+	case from.status == moveStatusQueenSideCastling && to.status == moveStatusEmpty:
+		r.renderShapedCastlingArrow(style, whiteQueenSideCastling, width)
+	case from.status == moveStatusEmpty && to.status == moveStatusKingSideCastling:
+		r.renderShapedCastlingArrow(style, blackKingSideCastling, width)
+	case from.status == moveStatusEmpty && to.status == moveStatusQueenSideCastling:
+		r.renderShapedCastlingArrow(style, blackQueenSideCastling, width)
+	}
+
+	return nil
+}
+
+// getArrowLine returns the center line of a straight or knight arrow, from the center of
+// the from square to the tip of the arrow, moved sideways by offset (see offsetPath). A move
+// that does not move returns nil.
+func (r *rendererMoves) getArrowLine(from, to alg, offset float64) []point {
+	dx, dy := to.x-from.x, to.y-from.y
+	if dx == 0 && dy == 0 {
+		return nil // Ignore no move
+	}
+
+	fx, fy := r.getSquareBox(from.coords()).center()
+	tx, ty := r.getSquareBox(to.coords()).center()
+	line := []point{{fx, fy}, {tx, ty}}
+
+	if dx != 0 && dy != 0 && abs(dx) != abs(dy) {
+		// Knight arrows turn in the square next to the target square
+		corner := from
+		if abs(dy) > abs(dx) {
+			corner.y = to.y
+		} else {
+			corner.x = to.x
+		}
+		cx, cy := r.getSquareBox(corner.coords()).center()
+		line = []point{line[0], {cx, cy}, line[1]}
+	}
+	line = clipPathAtRect(line, r.getSquareBox(to.coords()))
+
+	// Overlapping arrows are moved to the right
+	return offsetPath(line, offset)
+}
+
+// renderShapedCastlingArrow renders the king and rook arrows of a castling move, with the
+// same geometry as renderCastlingArrow.
+func (r *rendererMoves) renderShapedCastlingArrow(style *MoveStyle, castling castlingStatus, width float64) {
+	for _, line := range r.getCastlingLines(style, castling, width) {
+		r.renderPathArrow(style, line.color, line.points, width)
+	}
+}
+
+// castlingLine is the center line of one of the arrows of a castling move
+type castlingLine struct {
+	points []point
+	color  ColorRGBA
+}
+
+// getCastlingLines returns the center lines of the king and the rook arrows of a castling move.
+func (r *rendererMoves) getCastlingLines(style *MoveStyle, castling castlingStatus, width float64) []castlingLine {
+	var kingPos, rookPos string
+	var dir1, dir2 = directionEast, directionWest
+	var lengthFactor = 1.5
+
+	square := r.getSquareBox(0, 0)
+	cdy := style.Padding
+
+	switch castling {
+	case whiteKingSideCastling:
+		kingPos, rookPos = "E1", "H1"
+	case whiteQueenSideCastling:
+		kingPos, rookPos = "E1", "A1"
+		dir1, dir2 = directionWest, directionEast
+		lengthFactor = 2.5
+		cdy *= -1
+	case blackKingSideCastling:
+		kingPos, rookPos = "E8", "H8"
+	case blackQueenSideCastling:
+		kingPos, rookPos = "E8", "A8"
+		dir1, dir2 = directionWest, directionEast
+		lengthFactor = 2.5
+		cdy *= -1
+	}
+
+	line := func(pos string, length float64, dir direction) []point {
+		a, _ := newAlg(pos, r.inverted)
+		fx, fy := r.getSquareBox(a.coords()).center()
+		angle := gg.Radians(float64(dir))
+		forward := point{math.Sin(angle), -math.Cos(angle)}
+		right := point{-forward.y, forward.x}.mul(-cdy)
+		from := point{fx, fy}.add(right)
+		// The legacy arrows are length long, not including the arrow head
+		return []point{from, from.add(forward.mul(length + width*style.headLength()))}
+	}
+
+	return []castlingLine{
+		{line(kingPos, square.Width*1.5, dir1), style.Color},
+		{line(rookPos, square.Width*lengthFactor, dir2), style.Color2},
+	}
+}
+
+// renderPathArrow renders an arrow that follows the path, where the last point of the
+// path is the tip of the arrow head. The shaft has the given width, and by default the
+// head is twice as wide as the shaft, like the straight arrows.
+func (r *rendererMoves) renderPathArrow(style *MoveStyle, col ColorRGBA, path []point, width float64) {
+	if pathLength(path) == 0 {
+		return
+	}
+
+	// The arrow is drawn opaque on a separate layer, and then drawn using the alpha of
+	// the color, so that the parts of the arrow do not darken the places where they overlap.
+	layer := gg.NewContext(r.gg.Width(), r.gg.Height())
+	red, green, blue, _ := col.toRGBA()
+	layer.SetRGB(red, green, blue)
+	drawArrowShape(layer, style, path, width, 0)
+
+	if style.Outline == nil || style.Outline.Width <= 0 {
+		r.drawLayer(layer, col.A)
+		return
+	}
+
+	outline := gg.NewContext(r.gg.Width(), r.gg.Height())
+	outline.SetRGB(1, 1, 1)
+	drawArrowShape(outline, style, path, width, style.Outline.Width)
+	r.drawOutlinedLayer(layer, outline.AsMask(), col.RGBA, style.Outline.Color.RGBA)
+}
+
+// drawArrowShape draws the arrow, where all parts of the arrow have been made expand
+// pixels larger in every direction (used for outlines).
+func drawArrowShape(dc *gg.Context, style *MoveStyle, path []point, width, expand float64) {
+	length := pathLength(path)
+	headLength := math.Min(width*style.headLength(), length)
+	if style.DoubleHead {
+		headLength = math.Min(headLength, length/2)
+	}
+	halfHead := width * style.headWidth() / 2
+	open := style.HeadType == MoveHeadTypeOpen
+
+	// The shaft continues halfway into filled heads, to avoid gaps between them on curves.
+	// Open heads are drawn with thinner lines, so the shaft ends close to the tip.
+	inset := headLength / 2
+	if open {
+		inset = width / 4
+	}
+	start, end := 0.0, length-inset
+	if style.DoubleHead {
+		start = inset
+	}
+
+	dc.SetLineWidth(width + 2*expand)
+	dc.SetLineCap(gg.LineCapButt)
+	dc.SetLineJoin(gg.LineJoinRound)
+	if len(style.Dash) > 0 {
+		dc.SetDash(expandDash(style.Dash, expand)...)
+	}
+	for n, p := range pathSection(path, start-expand, end+expand) {
+		if n == 0 {
+			dc.MoveTo(p.x, p.y)
+		} else {
+			dc.LineTo(p.x, p.y)
+		}
+	}
+	dc.Stroke()
+	dc.SetDash()
+
+	drawArrowHead(dc, path[len(path)-1], pathSection(path, 0, length-headLength), halfHead, width, expand, open)
+	if style.DoubleHead {
+		reversed := reversePath(path)
+		drawArrowHead(dc, path[0], pathSection(reversed, 0, length-headLength), halfHead, width, expand, open)
+	} else if style.RoundTail {
+		dc.DrawCircle(path[0].x, path[0].y, width/2+expand)
+		dc.Fill()
+	}
+}
+
+// drawArrowHead draws an arrow head with its tip at tip, where the base of the head is
+// the last point of the shaft.
+func drawArrowHead(dc *gg.Context, tip point, shaft []point, halfHead, width, expand float64, open bool) {
+	base := shaft[len(shaft)-1]
+	dir := tip.sub(base).normalize()
+	side := point{-dir.y, dir.x}.mul(halfHead)
+
+	if open {
+		dc.SetLineWidth(width/2 + 2*expand)
+		dc.SetLineCap(gg.LineCapRound)
+		dc.MoveTo(base.x+side.x, base.y+side.y)
+		dc.LineTo(tip.x, tip.y)
+		dc.LineTo(base.x-side.x, base.y-side.y)
+		dc.Stroke()
+		return
+	}
+
+	dc.MoveTo(tip.x, tip.y)
+	dc.LineTo(base.x+side.x, base.y+side.y)
+	dc.LineTo(base.x-side.x, base.y-side.y)
+	dc.ClosePath()
+	if expand > 0 {
+		dc.SetLineWidth(2 * expand)
+		dc.FillPreserve()
+		dc.Stroke()
+	} else {
+		dc.Fill()
+	}
+}
+
+// expandDash returns the dash pattern, where each dash is expand pixels longer at both
+// ends, and the gaps are correspondingly shorter.
+func expandDash(dash []float64, expand float64) []float64 {
+	// Odd patterns are repeated, so that every length is used as both a dash and a gap
+	if len(dash)%2 == 1 {
+		dash = append(slices.Clone(dash), dash...)
+	}
+	result := make([]float64, len(dash))
+	for n, d := range dash {
+		if n%2 == 0 {
+			result[n] = d + 2*expand
+		} else {
+			result[n] = math.Max(0, d-2*expand)
+		}
+	}
+
+	return result
+}
+
+// pathSection returns the part of the path between the distances from and to along the
+// path. Distances outside the path extend the first or the last segment of the path.
+func pathSection(path []point, from, to float64) []point {
+	length := pathLength(path)
+	result := cutPath(path, math.Min(to, length))
+	if to > length {
+		last := result[len(result)-1]
+		dir := last.sub(path[len(path)-2]).normalize()
+		result[len(result)-1] = last.add(dir.mul(to - length))
+	}
+
+	switch {
+	case from == 0:
+		return result
+	case from < 0:
+		dir := path[1].sub(path[0]).normalize()
+		result[0] = result[0].sub(dir.mul(-from))
+		return result
+	}
+
+	// Remove the first part of the path
+	reversed := reversePath(result)
+	reversed = cutPath(reversed, pathLength(reversed)-from)
+
+	return reversePath(reversed)
+}
+
+// drawLayer draws a layer on top of the image, using the alpha value.
+func (r *rendererMoves) drawLayer(layer *gg.Context, alpha uint8) {
+	dst := r.gg.Image().(*image.RGBA)
+	draw.DrawMask(dst, dst.Bounds(), layer.Image(), image.Point{}, image.NewUniform(color.Alpha{A: alpha}),
+		image.Point{}, draw.Over)
+}
+
+// drawOutlinedLayer draws a layer, and the outline around it, on top of the image. The
+// arrow covers the outline, so the outline is only visible around the arrow, even when
+// the arrow is semi-transparent.
+func (r *rendererMoves) drawOutlinedLayer(layer *gg.Context, outline *image.Alpha, col, outlineColor color.RGBA) {
+	body := layer.AsMask()
+	b := body.Bounds()
+	img := image.NewRGBA(b)
+
+	for n := range body.Pix {
+		// The coverage of the arrow and the outline, 0-1
+		bc := float64(body.Pix[n]) / 255
+		oc := float64(outline.Pix[n]) / 255
+		ba := bc * float64(col.A) / 255
+		oa := (1 - bc) * oc * float64(outlineColor.A) / 255
+
+		// Premultiplied colors
+		img.Pix[n*4] = uint8(math.Round(ba*float64(col.R) + oa*float64(outlineColor.R)))
+		img.Pix[n*4+1] = uint8(math.Round(ba*float64(col.G) + oa*float64(outlineColor.G)))
+		img.Pix[n*4+2] = uint8(math.Round(ba*float64(col.B) + oa*float64(outlineColor.B)))
+		img.Pix[n*4+3] = uint8(math.Round((ba + oa) * 255))
+	}
+
+	dst := r.gg.Image().(*image.RGBA)
+	draw.Draw(dst, dst.Bounds(), img, image.Point{}, draw.Over)
+}
+
+// reversePath returns a reversed copy of the path.
+func reversePath(path []point) []point {
+	result := slices.Clone(path)
+	slices.Reverse(result)
+
+	return result
+}
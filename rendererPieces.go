@@ -4,8 +4,8 @@ import (
 	"bytes"
 	_ "embed"
 	"errors"
+	"fmt"
 	"image"
-	"os"
 	"strings"
 
 	"github.com/fogleman/gg"
@@ -15,6 +15,25 @@ import (
 //go:embed pieces.png
 var defaultPieces []byte
 
+// defaultPieceStyle is used for styled pieces and ghost pieces without a style
+var defaultPieceStyle = PieceStyle{Opacity: 0.5}
+
+// code2Piece maps the piece codes used in the settings file to pieces
+var code2Piece = map[string]chessPiece{
+	"WK": whiteKing,
+	"WQ": whiteQueen,
+	"WR": whiteRook,
+	"WN": whiteKnight,
+	"WB": whiteBishop,
+	"WP": whitePawn,
+	"BK": blackKing,
+	"BQ": blackQueen,
+	"BR": blackRook,
+	"BN": blackKnight,
+	"BB": blackBishop,
+	"BP": blackPawn,
+}
+
 type rendererPiece struct {
 	*Imager
 	ctx *ImageContext
@@ -36,6 +55,11 @@ func (r *rendererPiece) draw() error {
 		return err
 	}
 
+	styles, ghosts, err := r.getStyledPieces()
+	if err != nil {
+		return err
+	}
+
 	// FEN is validated before rendering starts,
 	// so it should be OK here.
 	fen := normalizeFEN(r.ctx.Fen)
@@ -44,30 +68,70 @@ func (r *rendererPiece) draw() error {
 	for rank, row := range fens {
 		for file, piece := range row {
 			if p := letter2Piece[piece]; p != noPiece {
-				r.gg.DrawImage(r.getImageAndPosition(r.ctx.pieces[p], file, rank, r.inverted))
+				img := r.ctx.pieces[p]
+				if style, ok := styles[image.Pt(file, rank)]; ok {
+					img = applyPieceStyle(img, style)
+				}
+				r.gg.DrawImage(r.getImageAndPosition(img, file, rank, r.inverted))
 			}
 		}
 	}
 
+	// Ghost pieces are drawn on top of the FEN pieces
+	for _, ghost := range ghosts {
+		img := applyPieceStyle(r.ctx.pieces[ghost.piece], ghost.style)
+		r.gg.DrawImage(r.getImageAndPosition(img, ghost.file, ghost.rank, r.inverted))
+	}
+
 	return nil
 }
 
-func (r *rendererPiece) init() error {
-	r.ctx.pieceMap = map[string]chessPiece{
-		"WK": whiteKing,
-		"WQ": whiteQueen,
-		"WR": whiteRook,
-		"WN": whiteKnight,
-		"WB": whiteBishop,
-		"WP": whitePawn,
-		"BK": blackKing,
-		"BQ": blackQueen,
-		"BR": blackRook,
-		"BN": blackKnight,
-		"BB": blackBishop,
-		"BP": blackPawn,
+// ghostPiece is a piece that is not in the FEN string, at a FEN file and rank
+type ghostPiece struct {
+	piece      chessPiece
+	file, rank int
+	style      *PieceStyle
+}
+
+// getStyledPieces returns the styles of the FEN pieces, by FEN file and rank, and the ghost pieces.
+func (r *rendererPiece) getStyledPieces() (map[image.Point]*PieceStyle, []ghostPiece, error) {
+	styles := map[image.Point]*PieceStyle{}
+	var ghosts []ghostPiece
+
+	for _, styled := range r.ctx.Pieces {
+		square, err := newAlg(styled.Square, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if square.status != moveStatusNormal {
+			return nil, nil, fmt.Errorf("invalid piece square : %q", styled.Square)
+		}
+
+		style := styled.Style
+		if style == nil {
+			style = &defaultPieceStyle
+		}
+
+		// FEN ranks start at rank 8
+		file, rank := square.x, invert(square.y)
+		if styled.Piece == "" {
+			styles[image.Pt(file, rank)] = style
+			continue
+		}
+
+		piece, ok := code2Piece[strings.ToUpper(styled.Piece)]
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid ghost piece : %q", styled.Piece)
+		}
+		ghosts = append(ghosts, ghostPiece{piece: piece, file: file, rank: rank, style: style})
 	}
 
+	return styles, ghosts, nil
+}
+
+func (r *rendererPiece) init() error {
+	r.ctx.pieceMap = code2Piece
+
 	r.ctx.embeddedPieces = []PieceRectangle{
 		{whiteKing, Rectangle{0, 0, 333, 333}},
 		{whiteQueen, Rectangle{333, 0, 333, 333}},
@@ -106,7 +170,7 @@ func (r *rendererPiece) loadPieces() error {
 		}
 	case piecesTypeImages:
 		for _, piece := range r.settings.Pieces.Images.Pieces {
-			f, err := os.Open(piece.Path)
+			f, err := r.openAsset(piece.Path)
 			if err != nil {
 				return err
 			}
@@ -118,7 +182,7 @@ func (r *rendererPiece) loadPieces() error {
 			r.ctx.pieces[r.ctx.pieceMap[strings.ToUpper(piece.Piece)]] = r.resize(img)
 		}
 	case piecesTypeImageMap:
-		f, err := os.Open(r.settings.Pieces.ImageMap.Path)
+		f, err := r.openAsset(r.settings.Pieces.ImageMap.Path)
 		if err != nil {
 			return err
 		}
@@ -133,6 +197,15 @@ func (r *rendererPiece) loadPieces() error {
 		}
 	}
 
+	// Apply the effects once, to the cached piece images
+	for piece, img := range r.ctx.pieces {
+		effects := r.settings.Pieces.WhiteEffects
+		if piece >= blackPawn {
+			effects = r.settings.Pieces.BlackEffects
+		}
+		r.ctx.pieces[piece] = applyPieceEffects(img, effects, r.getScale())
+	}
+
 	return nil
 }
 
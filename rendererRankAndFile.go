@@ -37,7 +37,7 @@ func (r *rendererRankAndFile) draw() error {
 	case rankAndFileTypeInBorder:
 		r.drawRanksAndFiles(0, 0)
 	case rankAndFileTypeInSquares:
-		const padding = 3
+		padding := r.scaled(3)
 		square := float64(r.getBoardBox().Width) / 8
 		diff := (square - float64(fontSize) - padding) / 2
 		r.drawRanksAndFiles(diff, diff)
@@ -52,7 +52,7 @@ func (r *rendererRankAndFile) shouldDrawRankAndFile() bool {
 	border := float64(r.settings.Border.Width)
 	return r.settings.Board.Type == boardTypeImage ||
 		r.settings.RankAndFile.Type == rankAndFileTypeNone ||
-		border < borderLimit
+		border < r.scaled(borderLimit)
 }
 
 func (r *rendererRankAndFile) drawRanksAndFiles(dx, dy float64) {
@@ -60,7 +60,7 @@ func (r *rendererRankAndFile) drawRanksAndFiles(dx, dy float64) {
 
 	var diff float64
 	if r.useInternalFont {
-		diff -= 2
+		diff -= r.scaled(2)
 	}
 
 	for _, rfBox := range rfBoxes {
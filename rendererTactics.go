@@ -0,0 +1,77 @@
+package chessImager
+
+// drawHangingPieces highlights the pieces that are attacked and not defended.
+func (r *rendererHighlight) drawHangingPieces() error {
+	style := r.getTacticsStyle(r.ctx.TacticalHints.Style)
+	if !style.Hanging {
+		return nil
+	}
+	pos, err := newPosition(r.ctx.Fen)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range pos.hangingPieces() {
+		b := r.getBoardSquareBox(s)
+		err = r.highlight(b, &style.HangingStyle)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getTacticsMoves returns the pins, discovered attacks and forks as moves, that are
+// rendered before the moves of the image context.
+func (r *rendererMoves) getTacticsMoves() ([]layoutMove, error) {
+	if r.ctx.TacticalHints == nil {
+		return nil, nil
+	}
+	style := r.getTacticsStyle(r.ctx.TacticalHints.Style)
+	pos, err := newPosition(r.ctx.Fen)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []layoutMove
+	// Lines pass through the pinned piece or the piece that can move away, which makes them path
+	// moves. Like all arrows, they are moved sideways by the layout if they enter a square in
+	// the same place as another arrow, for example a pin line and a move that both end on the king.
+	line := func(l tacticalLine, style *MoveStyle) layoutMove {
+		return layoutMove{
+			Move:  Move{From: l.from.String(), Via: []string{l.through.String()}, To: l.to.String()},
+			style: style,
+		}
+	}
+	if style.Pins {
+		for _, pin := range pos.pins() {
+			moves = append(moves, line(pin, &style.PinStyle))
+		}
+	}
+	if style.DiscoveredAttacks {
+		for _, attack := range pos.discoveredAttacks() {
+			moves = append(moves, line(attack, &style.DiscoveredAttackStyle))
+		}
+	}
+	if style.Forks {
+		for _, f := range pos.forks() {
+			for _, target := range f.targets {
+				moves = append(moves, layoutMove{
+					Move:  Move{From: f.from.String(), To: target.String()},
+					style: &style.ForkStyle,
+				})
+			}
+		}
+	}
+
+	return moves, nil
+}
+
+// getTacticsStyle returns the style of the tactical hints, or the default style.
+func (i *Imager) getTacticsStyle(style *TacticsStyle) *TacticsStyle {
+	if style == nil {
+		return &i.settings.TacticsStyle
+	}
+	return style.scale(i.getScale())
+}
@@ -0,0 +1,81 @@
+package chessImager
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// SaveSettings writes the current settings to w, as a pretty-printed JSON document.
+// The output is canonical : the fields are always written in the same order, and
+// colors are written as lower case #rrggbbaa strings. Loading the document with
+// LoadSettings results in settings that are equal to the current settings.
+func (i *Imager) SaveSettings(w io.Writer) error {
+	data, err := json.MarshalIndent(i.settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeSettings(w, data)
+}
+
+// SaveSettingsDiff writes the settings that differ from the default settings to w, as a
+// pretty-printed JSON document, with the keys sorted alphabetically. Since LoadSettings
+// merges settings files on top of the default settings, loading the document results in
+// settings that are equal to the current settings. Lists (like order) are written in full,
+// and settings that are empty now, but not in the default settings, are written as null.
+func (i *Imager) SaveSettingsDiff(w io.Writer) error {
+	defaults, err := loadDefaultSettings()
+	if err != nil {
+		return err
+	}
+	base, err := defaults.toMap()
+	if err != nil {
+		return err
+	}
+	current, err := i.settings.toMap()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(diffMaps(base, current), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeSettings(w, data)
+}
+
+// writeSettings writes a JSON settings document to w, followed by a new line.
+func writeSettings(w io.Writer, data []byte) error {
+	_, err := w.Write(append(data, '\n'))
+
+	return err
+}
+
+// diffMaps returns the values in current that differ from the values in base.
+// Objects are compared recursively, and all other values are compared as a whole.
+// Keys that are in base, but not in current (like an empty list that is omitted),
+// get a null value, so that they are unset when the diff is merged into base.
+func diffMaps(base, current map[string]any) map[string]any {
+	result := map[string]any{}
+	for key := range base {
+		if _, ok := current[key]; !ok {
+			result[key] = nil
+		}
+	}
+	for key, value := range current {
+		bm, ok1 := base[key].(map[string]any)
+		cm, ok2 := value.(map[string]any)
+		switch {
+		case ok1 && ok2:
+			if diff := diffMaps(bm, cm); len(diff) > 0 {
+				result[key] = diff
+			}
+		case !reflect.DeepEqual(base[key], value):
+			result[key] = value
+		}
+	}
+
+	return result
+}
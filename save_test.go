@@ -0,0 +1,115 @@
+package chessImager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveSettingsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, theme := range Themes() {
+		imager := NewImager()
+		if err := imager.UseTheme(theme); err != nil {
+			t.Fatalf("UseTheme(%v) failed : %v", theme, err)
+		}
+
+		for _, diff := range []bool{false, true} {
+			var buf bytes.Buffer
+			save := imager.SaveSettings
+			if diff {
+				save = imager.SaveSettingsDiff
+			}
+			if err := save(&buf); err != nil {
+				t.Fatalf("%v : save failed : %v", theme, err)
+			}
+
+			path := filepath.Join(t.TempDir(), "settings.json")
+			if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+				t.Fatalf("failed to write settings : %v", err)
+			}
+			loaded := &Imager{}
+			if err := loaded.LoadSettings(path); err != nil {
+				t.Fatalf("%v : LoadSettings() failed : %v", theme, err)
+			}
+			if !reflect.DeepEqual(loaded.settings, imager.settings) {
+				t.Errorf("%v (diff=%v) : got = %+v, want %+v", theme, diff, loaded.settings, imager.settings)
+			}
+
+			// Saving the loaded settings again, must give the same document
+			var again bytes.Buffer
+			save = loaded.SaveSettings
+			if diff {
+				save = loaded.SaveSettingsDiff
+			}
+			if err := save(&again); err != nil {
+				t.Fatalf("%v : save failed : %v", theme, err)
+			}
+			if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+				t.Errorf("%v (diff=%v) : output is not canonical", theme, diff)
+			}
+		}
+	}
+}
+
+func TestSaveSettingsDiff(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	imager.settings.Border.Width = 10
+	imager.settings.MoveStyle.Color = ColorRGBA{RGBA: hexMust(t, "#FF0000")}
+
+	var buf bytes.Buffer
+	if err := imager.SaveSettingsDiff(&buf); err != nil {
+		t.Fatalf("SaveSettingsDiff() failed : %v", err)
+	}
+
+	want := `{
+  "border": {
+    "width": 10
+  },
+  "move_style": {
+    "color": "#ff0000ff"
+  }
+}
+`
+	if buf.String() != want {
+		t.Errorf("SaveSettingsDiff() got = %v, want %v", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := NewImager().SaveSettingsDiff(&buf); err != nil {
+		t.Fatalf("SaveSettingsDiff() failed : %v", err)
+	}
+	if buf.String() != "{}\n" {
+		t.Errorf("SaveSettingsDiff() of the default settings got = %v, want {}", buf.String())
+	}
+}
+
+func TestSaveSettingsDiffCleared(t *testing.T) {
+	t.Parallel()
+
+	// The dash of the discovered attack style is [10, 6] in the default settings
+	imager := NewImager()
+	imager.settings.TacticsStyle.DiscoveredAttackStyle.Dash = nil
+
+	var buf bytes.Buffer
+	if err := imager.SaveSettingsDiff(&buf); err != nil {
+		t.Fatalf("SaveSettingsDiff() failed : %v", err)
+	}
+	if !strings.Contains(buf.String(), `"dash": null`) {
+		t.Errorf("SaveSettingsDiff() did not unset the dash :\n%s", buf.String())
+	}
+
+	loaded := NewImager()
+	if err := loaded.ApplySettings(&buf); err != nil {
+		t.Fatalf("ApplySettings() failed : %v", err)
+	}
+	if !reflect.DeepEqual(loaded.settings, imager.settings) {
+		t.Errorf("loaded settings got = %+v, want %+v", loaded.settings, imager.settings)
+	}
+}
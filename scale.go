@@ -0,0 +1,217 @@
+package chessImager
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// Smallest square size (in pixels) that RenderSized accepts
+	minLegibleSquareSize = 12
+	// Smallest font size (in pixels) that RenderSized accepts for the rank and file indicators
+	minLegibleFontSize = 6
+)
+
+// withScale returns a copy of the imager, where all geometric settings
+// (sizes, widths, font sizes and paddings) have been multiplied by the scale
+// factor. Settings that are relative to the square size (like factors) are
+// scaled automatically, since the squares are scaled.
+// A scale of 0 or 1 returns the imager itself.
+func (i *Imager) withScale(scale float64) (*Imager, error) {
+	switch {
+	case scale < 0 || math.IsNaN(scale) || math.IsInf(scale, 0):
+		return nil, errors.New("invalid scale, must be a positive number")
+	case scale == 0 || scale == 1:
+		return i, nil
+	}
+
+	c := *i
+	c.settings, c.scale = i.settings.scale(scale), scale
+
+	return &c, nil
+}
+
+// getScale returns the scale factor that is used when rendering.
+func (i *Imager) getScale() float64 {
+	if i.scale == 0 {
+		return 1
+	}
+	return i.scale
+}
+
+// scaled scales a hard coded pixel value by the current scale factor.
+func (i *Imager) scaled(v float64) float64 {
+	return v * i.getScale()
+}
+
+// getFitScale returns the largest scale factor that makes the rendered image
+// fit inside width x height. For the default board, the scale is adjusted so
+// that each square gets a whole number of pixels.
+func (i *Imager) getFitScale(width, height int) (float64, error) {
+	if width <= 0 || height <= 0 {
+		return 0, fmt.Errorf("invalid size %dx%d", width, height)
+	}
+
+	size, err := i.getBoardSize()
+	if err != nil {
+		return 0, err
+	}
+	scale := math.Min(float64(width)/float64(size.Dx()), float64(height)/float64(size.Dy()))
+
+	if i.settings.Board.Type == boardTypeDefault && i.settings.Board.Default.Size > 0 {
+		boardSize := float64(i.settings.Board.Default.Size)
+		square := math.Floor(boardSize * scale / 8)
+		for ; square > 0; square-- {
+			scale = square * 8 / boardSize
+			c := *i
+			c.settings, c.scale = i.settings.scale(scale), scale
+			fitted, err := c.getBoardSize()
+			if err != nil {
+				return 0, err
+			}
+			if fitted.Dx() <= width && fitted.Dy() <= height {
+				break
+			}
+		}
+		if square <= 0 {
+			scale = 0
+		}
+	}
+
+	square := i.getBoardBox().Width / 8 * scale
+	if square < minLegibleSquareSize {
+		return 0, fmt.Errorf("size %dx%d is too small, the squares would be %.1f pixels (minimum is %d)",
+			width, height, square, minLegibleSquareSize)
+	}
+
+	fontSize := float64(i.settings.RankAndFile.FontSize) * scale
+	if i.settings.Board.Type == boardTypeDefault && i.settings.RankAndFile.Type != rankAndFileTypeNone &&
+		fontSize < minLegibleFontSize {
+		return 0, fmt.Errorf("size %dx%d is too small, the rank and file font size would be %.1f pixels "+
+			"(minimum is %d)", width, height, fontSize, minLegibleFontSize)
+	}
+
+	return scale, nil
+}
+
+// scale returns a copy of the settings where all geometric settings
+// have been multiplied by f.
+func (s *Settings) scale(f float64) *Settings {
+	c := *s
+
+	c.Border.Width = scaleInt(s.Border.Width, f)
+	c.Board.Default.Size = scaleInt(s.Board.Default.Size, f)
+	c.Board.Image.Rect = Rectangle{
+		X:      s.Board.Image.Rect.X * f,
+		Y:      s.Board.Image.Rect.Y * f,
+		Width:  s.Board.Image.Rect.Width * f,
+		Height: s.Board.Image.Rect.Height * f,
+	}
+	c.RankAndFile.FontSize = scaleInt(s.RankAndFile.FontSize, f)
+	c.HighlightStyle = *s.HighlightStyle.scale(f)
+	c.AnnotationStyle = *s.AnnotationStyle.scale(f)
+	c.MoveStyle = *s.MoveStyle.scale(f)
+	c.HeatmapStyle = *s.HeatmapStyle.scale(f)
+	c.TacticsStyle = *s.TacticsStyle.scale(f)
+	c.LegalMovesStyle = *s.LegalMovesStyle.scale(f)
+	c.LastMoveStyle = *s.LastMoveStyle.scale(f)
+
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *HighlightStyle) scale(f float64) *HighlightStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.Width = scaleInt(s.Width, f)
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *AnnotationStyle) scale(f float64) *AnnotationStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.Size = scaleInt(s.Size, f)
+	c.FontSize = scaleInt(s.FontSize, f)
+	c.BorderWidth = scaleInt(s.BorderWidth, f)
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *MoveStyle) scale(f float64) *MoveStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.Padding = s.Padding * f
+	c.LabelFontSize = scaleInt(s.LabelFontSize, f)
+	if s.Outline != nil {
+		o := *s.Outline
+		o.Width *= f
+		c.Outline = &o
+	}
+	if s.Dash != nil {
+		c.Dash = make([]float64, len(s.Dash))
+		for n, d := range s.Dash {
+			c.Dash[n] = d * f
+		}
+	}
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *HeatmapStyle) scale(f float64) *HeatmapStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.LegendFontSize = scaleInt(s.LegendFontSize, f)
+	c.LabelFontSize = scaleInt(s.LabelFontSize, f)
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *TacticsStyle) scale(f float64) *TacticsStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.HangingStyle = *s.HangingStyle.scale(f)
+	c.PinStyle = *s.PinStyle.scale(f)
+	c.DiscoveredAttackStyle = *s.DiscoveredAttackStyle.scale(f)
+	c.ForkStyle = *s.ForkStyle.scale(f)
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *LegalMovesStyle) scale(f float64) *LegalMovesStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.SelectedStyle = *s.SelectedStyle.scale(f)
+	c.MoveStyle = *s.MoveStyle.scale(f)
+	c.CaptureStyle = *s.CaptureStyle.scale(f)
+	return &c
+}
+
+// scale returns a copy of the style, scaled by f.
+func (s *LastMoveStyle) scale(f float64) *LastMoveStyle {
+	if f == 1 {
+		return s
+	}
+	c := *s
+	c.FromStyle = *s.FromStyle.scale(f)
+	c.ToStyle = *s.ToStyle.scale(f)
+	c.ArrowStyle = *s.ArrowStyle.scale(f)
+	return &c
+}
+
+func scaleInt(v int, f float64) int {
+	return int(math.Round(float64(v) * f))
+}
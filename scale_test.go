@@ -0,0 +1,161 @@
+package chessImager
+
+import (
+	"image"
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	imager := NewImager()
+
+	for _, scale := range []float64{0, 1, 2, 3} {
+		ctx := imager.NewContext(fen).AddHighlight("e7").AddAnnotation("e7", "!!").AddMove("e1", "e7")
+		ctx.Scale = scale
+
+		img, err := imager.RenderWithContext(ctx)
+		if err != nil {
+			t.Fatalf("failed to render with scale %v : %v", scale, err)
+		}
+
+		want := 648
+		if scale > 1 {
+			want = int(648 * scale)
+		}
+		if got := img.Bounds().Size(); got != image.Pt(want, want) {
+			t.Errorf("scale %v : got size = %v, want %v", scale, got, want)
+		}
+	}
+
+	// Scaling must not change the settings of the imager
+	if imager.settings.Board.Default.Size != 600 || imager.settings.Border.Width != 24 {
+		t.Errorf("scaling changed the imager settings")
+	}
+}
+
+func TestScaleSamplesSameColors(t *testing.T) {
+	t.Parallel()
+
+	const fen = "8/8/8/8/8/8/8/8 w - - 0 1"
+	imager := NewImager()
+
+	img1, err := imager.Render(fen)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+	ctx := imager.NewContext(fen)
+	ctx.Scale = 2
+	img2, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	// The center of every square should have the same color in both images
+	for _, square := range []string{"a1", "b1", "e4", "h8"} {
+		r := imager.SquareRect(nil, square, false)
+		x, y := (r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2
+		c1, c2 := img1.At(x, y), img2.At(x*2, y*2)
+		if c1 != c2 {
+			t.Errorf("square %v : got color %v, want %v", square, c2, c1)
+		}
+	}
+}
+
+func TestScaleInvalid(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+	ctx.Scale = -1
+
+	_, err := imager.RenderWithContext(ctx)
+	if err == nil {
+		t.Errorf("negative scale returned no error")
+	}
+}
+
+func TestSettingsScale(t *testing.T) {
+	t.Parallel()
+
+	s := NewImager().settings.scale(2)
+
+	if s.Border.Width != 48 || s.Board.Default.Size != 1200 || s.RankAndFile.FontSize != 32 {
+		t.Errorf("scale() got border %v, size %v, font size %v", s.Border.Width, s.Board.Default.Size,
+			s.RankAndFile.FontSize)
+	}
+	if s.HighlightStyle.Width != 8 || s.AnnotationStyle.Size != 30 || s.AnnotationStyle.FontSize != 26 ||
+		s.MoveStyle.Padding != 20 {
+		t.Errorf("scale() did not scale styles correctly")
+	}
+	if s.MoveStyle.Factor != 0.15 || s.Pieces.Factor != 1 {
+		t.Errorf("scale() should not scale relative factors")
+	}
+}
+
+func TestRenderSized(t *testing.T) {
+	t.Parallel()
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+	imager := NewImager()
+
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{"square", 300, 300},
+		{"wide", 500, 300},
+		{"high", 300, 500},
+		{"large", 1000, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := imager.RenderSized(imager.NewContext(fen), tt.width, tt.height)
+			if err != nil {
+				t.Fatalf("failed to render : %v", err)
+			}
+			if got, want := img.Bounds().Size(), image.Pt(tt.width, tt.height); got != want {
+				t.Errorf("RenderSized() got size = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRenderSizedFitsBoard(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+
+	scale, err := imager.getFitScale(500, 300)
+	if err != nil {
+		t.Fatalf("getFitScale() failed : %v", err)
+	}
+
+	// Squares must be a whole number of pixels, and the board must fit
+	square := 600 * scale / 8
+	if square != float64(int(square)) {
+		t.Errorf("getFitScale() square size %v is not a whole number", square)
+	}
+	si, _ := imager.withScale(scale)
+	size, _ := si.getBoardSize()
+	if size.Dx() > 300 || size.Dy() > 300 || size.Dx() < 290 {
+		t.Errorf("getFitScale() board size %v does not fit 500x300", size)
+	}
+}
+
+func TestRenderSizedTooSmall(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+
+	_, err := imager.RenderSized(ctx, 60, 60)
+	if err == nil {
+		t.Errorf("RenderSized() with too small size returned no error")
+	}
+	_, err = imager.RenderSized(ctx, 0, 600)
+	if err == nil {
+		t.Errorf("RenderSized() with zero width returned no error")
+	}
+}
@@ -0,0 +1,164 @@
+package chessImager
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaID is the JSON Schema dialect used by SettingsSchema.
+const schemaID = "https://json-schema.org/draft/2020-12/schema"
+
+// colorPattern matches the colors that ColorRGBA accepts, see parseColor.
+const colorPattern = `^\s*(#?([0-9A-Fa-f]{3,4}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})|[A-Za-z]+|(rgb|rgba|hsl|hsla)\(.*\)|\$.+)\s*$`
+
+// colorDescription describes the colors that ColorRGBA accepts.
+const colorDescription = "Hex color (#RGB, #RGBA, #RRGGBB or #RRGGBBAA), CSS color name, rgb(), rgba(), hsl(), " +
+	"hsla() or a reference to a palette color ($name)"
+
+var colorType = reflect.TypeOf(ColorRGBA{})
+
+// schemaEnums contains the allowed values, and a description of them, for the enum types in the settings.
+var schemaEnums = map[reflect.Type]struct {
+	max         int
+	description string
+}{
+	reflect.TypeOf(boardType(0)):         {int(boardTypeImage), "0 = Default, 1 = Image"},
+	reflect.TypeOf(rankAndFileType(0)):   {int(rankAndFileTypeInSquares), "0 = None, 1 = InBorder, 2 = InSquares"},
+	reflect.TypeOf(piecesType(0)):        {int(piecesTypeImageMap), "0 = Embedded pieces, 1 = Images, 2 = ImageMap"},
+	reflect.TypeOf(HighlightType(0)):     {int(HighlightTypeX), "0 = Full, 1 = Border, 2 = Circle, 3 = FilledCircle, 4 = X"},
+	reflect.TypeOf(PositionType(0)):      {int(PositionTypeMiddle), "0 = TopLeft, 1 = TopRight, 2 = BottomRight, 3 = BottomLeft, 4 = Middle"},
+	reflect.TypeOf(MoveType(0)):          {int(MoveTypeArrow), "0 = Dots, 1 = Arrow"},
+	reflect.TypeOf(MoveLabelPosition(0)): {int(MoveLabelPositionHead), "0 = Middle, 1 = Head"},
+	reflect.TypeOf(MoveHeadType(0)):      {int(MoveHeadTypeOpen), "0 = Filled, 1 = Open"},
+	reflect.TypeOf(fillType(0)):          {int(fillTypeNoise), "0 = Linear gradient, 1 = Radial gradient, 2 = Texture, 3 = Noise"},
+}
+
+// schemaOverrides contains extra schema keywords for specific settings, by JSON path.
+// Array items are referenced with [], for example pieces.images.pieces[].piece.
+var schemaOverrides = map[string]map[string]any{
+	"order": {
+		"description": "Render order, must contain each renderer index (0-6) once",
+		"minItems":    7,
+		"maxItems":    7,
+		"uniqueItems": true,
+	},
+	"order[]":                         {"minimum": 0, "maximum": 6},
+	"pieces.images.pieces[].piece":    {"enum": pieceCodes()},
+	"pieces.image_map.pieces[].piece": {"enum": pieceCodes()},
+	"board.default.size":              {"exclusiveMinimum": 0},
+	"pieces.factor":                   {"exclusiveMinimum": 0},
+	"border.width":                    {"minimum": 0},
+	"highlight_style.width":           {"minimum": 0},
+	"annotation_style.border_width":   {"minimum": 0},
+	"annotation_style.size":           {"exclusiveMinimum": 0},
+	"annotation_style.font_size":      {"exclusiveMinimum": 0},
+	"rank_and_file.font_size":         {"minimum": 0},
+	"move_style.factor":               {"exclusiveMinimum": 0},
+	"board.image.path":                {"description": "Path to the board image, relative to the settings file"},
+	"pieces.images.pieces[].path":     {"description": "Path to the piece image, relative to the settings file"},
+	"pieces.image_map.path":           {"description": "Path to the image map, relative to the settings file"},
+	"font_style.path":                 {"description": "Path to a TTF font, relative to the settings file"},
+	"highlight_style.factor":          {"minimum": 0},
+	"move_style.padding":              {"minimum": 0},
+	"move_style.label_font_size":      {"exclusiveMinimum": 0},
+	"move_style.weight_width":         {"minimum": 0, "maximum": 1},
+	"move_style.weight_opacity":       {"minimum": 0, "maximum": 1},
+	"move_style.outline.width":        {"exclusiveMinimum": 0},
+	"move_style.dash[]":               {"minimum": 0},
+	"move_style.head_length":          {"minimum": 0},
+	"move_style.head_width":           {"minimum": 0},
+	"heatmap_style.colormap":          {"enum": colormapNames()},
+	"heatmap_style.colors":            {"minItems": 2},
+	"heatmap_style.opacity":           {"minimum": 0, "maximum": 1},
+	"heatmap_style.legend_font_size":  {"minimum": 0},
+	"heatmap_style.label_font_size":   {"minimum": 0},
+	"palette":                         {"description": "Named colors, that can be referenced by all colors as $name"},
+}
+
+// SettingsSchema returns a JSON Schema that describes the settings files. Editors can
+// use the schema to autocomplete and validate settings and theme files. Settings files
+// can refer to the schema using the "$schema" key, which is ignored when loading them.
+// A copy of the schema can be found in config/settings.schema.json.
+func SettingsSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(Settings{}), "")
+	schema["$schema"] = schemaID
+	schema["title"] = "ChessImager settings"
+	schema["properties"].(map[string]any)["$schema"] = map[string]any{"type": "string"}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor returns the schema for a type, where path is the JSON path of the setting.
+func schemaFor(t reflect.Type, path string) map[string]any {
+	var schema map[string]any
+
+	enum, isEnum := schemaEnums[t]
+	switch {
+	case t == colorType:
+		schema = map[string]any{"type": "string", "pattern": colorPattern, "description": colorDescription}
+	case isEnum:
+		values := make([]int, enum.max+1)
+		for n := range values {
+			values[n] = n
+		}
+		schema = map[string]any{"type": "integer", "enum": values, "description": enum.description}
+	default:
+		switch t.Kind() {
+		case reflect.Pointer:
+			return schemaFor(t.Elem(), path)
+		case reflect.Struct:
+			properties := map[string]any{}
+			for n := 0; n < t.NumField(); n++ {
+				field := t.Field(n)
+				name := strings.Split(field.Tag.Get("json"), ",")[0]
+				if !field.IsExported() || name == "-" {
+					continue
+				}
+				if name == "" {
+					name = field.Name
+				}
+				properties[name] = schemaFor(field.Type, strings.TrimPrefix(path+"."+name, "."))
+			}
+			schema = map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+		case reflect.Map:
+			schema = map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), path+"[]")}
+		case reflect.Slice:
+			schema = map[string]any{"type": "array", "items": schemaFor(t.Elem(), path+"[]")}
+		case reflect.Array:
+			schema = map[string]any{
+				"type":     "array",
+				"items":    schemaFor(t.Elem(), path+"[]"),
+				"minItems": t.Len(),
+				"maxItems": t.Len(),
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			schema = map[string]any{"type": "integer"}
+		case reflect.Float32, reflect.Float64:
+			schema = map[string]any{"type": "number"}
+		case reflect.Bool:
+			schema = map[string]any{"type": "boolean"}
+		default:
+			schema = map[string]any{"type": "string"}
+		}
+	}
+
+	for key, value := range schemaOverrides[path] {
+		schema[key] = value
+	}
+
+	return schema
+}
+
+// pieceCodes returns the valid piece codes, in both upper and lower case.
+func pieceCodes() []string {
+	var codes []string
+	for _, color := range "WB" {
+		for _, piece := range "KQRBNP" {
+			code := string(color) + string(piece)
+			codes = append(codes, code, strings.ToLower(code))
+		}
+	}
+
+	return codes
+}
@@ -21,9 +21,16 @@ package chessImager
 // HighlightStyle : Defines how a highlighted square should be rendered
 // AnnotationStyle : Defines how an annotation should be rendered
 // MoveStyle : Defines how a move should be rendered
+// HeatmapStyle : Defines how heatmaps should be rendered
+// TacticsStyle : Defines which tactical hints should be rendered, and how
+// LegalMovesStyle : Defines how the legal moves of a piece should be rendered
+// LastMoveStyle : Defines how the last move should be rendered
+// Palette : Named colors, that can be referenced by all colors as "$name"
 type Settings struct {
 	Order []int `json:"order"`
 
+	Palette map[string]ColorRGBA `json:"palette,omitempty"`
+
 	Border      Border      `json:"border"`
 	Board       Board       `json:"board"`
 	RankAndFile RankAndFile `json:"rank_and_file"`
@@ -33,14 +40,20 @@ type Settings struct {
 	HighlightStyle  HighlightStyle  `json:"highlight_style"`
 	AnnotationStyle AnnotationStyle `json:"annotation_style"`
 	MoveStyle       MoveStyle       `json:"move_style"`
+	HeatmapStyle    HeatmapStyle    `json:"heatmap_style"`
+	TacticsStyle    TacticsStyle    `json:"tactics_style"`
+	LegalMovesStyle LegalMovesStyle `json:"legal_moves_style"`
+	LastMoveStyle   LastMoveStyle   `json:"last_move_style"`
 }
 
 // Border settings for the chessboard
 // Width: Width of the border around the chessboard
 // Color: Color of the border around the chessboard
+// Fill: Optional fill of the border, used instead of Color. Gradients cover the entire image.
 type Border struct {
 	Width int       `json:"width"`
 	Color ColorRGBA `json:"color"`
+	Fill  *Fill     `json:"fill,omitempty"`
 }
 
 // Board settings
@@ -57,10 +70,32 @@ type Board struct {
 // Size : Size of the board excluding the border. Normally this value should be divisible by 8.
 // White : The color of the light squares
 // Black : The color of the dark squares
+// WhiteFill : Optional fill of the light squares, used instead of White. Gradients cover the entire board.
+// BlackFill : Optional fill of the dark squares, used instead of Black. Gradients cover the entire board.
 type BoardDefault struct {
-	Size  int       `json:"size"`
-	White ColorRGBA `json:"white"`
-	Black ColorRGBA `json:"black"`
+	Size      int       `json:"size"`
+	White     ColorRGBA `json:"white"`
+	Black     ColorRGBA `json:"black"`
+	WhiteFill *Fill     `json:"white_fill,omitempty"`
+	BlackFill *Fill     `json:"black_fill,omitempty"`
+}
+
+// Fill defines how an area is painted, when a single color is not enough.
+// Type : 0 = Linear gradient, 1 = Radial gradient, 2 = Texture, 3 = Noise
+// Colors : The gradient colors, evenly spaced (Type=0,1), or the two colors that the noise blends between (Type=3)
+// Angle : The direction of a linear gradient in degrees, 0 = left to right, 90 = top to bottom (Type=0)
+// Path : Path to a texture image, that is tiled over the area (Type=2)
+// RandomOffset : Shift the texture a random amount for each square, so that the squares look different (Type=2)
+// Size : The size of the noise features in pixels, default = 16 (Type=3)
+// Seed : The seed for the random offsets and the noise, the same seed always gives the same image (Type=2,3)
+type Fill struct {
+	Type         fillType    `json:"type"`
+	Colors       []ColorRGBA `json:"colors,omitempty"`
+	Angle        float64     `json:"angle,omitempty"`
+	Path         string      `json:"path,omitempty"`
+	RandomOffset bool        `json:"random_offset,omitempty"`
+	Size         float64     `json:"size,omitempty"`
+	Seed         int64       `json:"seed,omitempty"`
 }
 
 // BoardImage represents settings for rendering the background image of a chessboard (Board.Type=1)
@@ -114,11 +149,54 @@ type HighlightStyle struct {
 // Type: 0 = Embedded pieces, 1 = Images, 2 ImageMap
 // Images : Only used if Type=1
 // ImageMap : Only used if Type=2
+// WhiteEffects : Optional effects for the white pieces
+// BlackEffects : Optional effects for the black pieces
 type Pieces struct {
-	Factor   float64    `json:"factor"`
-	Type     piecesType `json:"type"`
-	Images   Images     `json:"images"`
-	ImageMap ImageMap   `json:"image_map"`
+	Factor       float64       `json:"factor"`
+	Type         piecesType    `json:"type"`
+	Images       Images        `json:"images"`
+	ImageMap     ImageMap      `json:"image_map"`
+	WhiteEffects *PieceEffects `json:"white_effects,omitempty"`
+	BlackEffects *PieceEffects `json:"black_effects,omitempty"`
+}
+
+// PieceEffects represents effects that are applied to the pieces of one side, to make
+// them more legible on busy boards. The effects are drawn below the piece.
+// Shadow : A soft drop shadow
+// Outline : An outline around the piece
+// Glow : A soft glow around the piece
+type PieceEffects struct {
+	Shadow  *PieceShadow  `json:"shadow,omitempty"`
+	Outline *PieceOutline `json:"outline,omitempty"`
+	Glow    *PieceGlow    `json:"glow,omitempty"`
+}
+
+// PieceShadow represents a soft drop shadow below a piece.
+// OffsetX : Horizontal offset of the shadow in pixels
+// OffsetY : Vertical offset of the shadow in pixels
+// Blur : Blur radius of the shadow in pixels, 0 gives a sharp shadow
+// Color : The color of the shadow
+type PieceShadow struct {
+	OffsetX float64   `json:"offset_x"`
+	OffsetY float64   `json:"offset_y"`
+	Blur    float64   `json:"blur"`
+	Color   ColorRGBA `json:"color"`
+}
+
+// PieceOutline represents an outline around a piece, that follows the shape of the piece.
+// Width : Width of the outline in pixels
+// Color : The color of the outline
+type PieceOutline struct {
+	Width float64   `json:"width"`
+	Color ColorRGBA `json:"color"`
+}
+
+// PieceGlow represents a soft glow around a piece.
+// Radius : How far the glow reaches outside the piece, in pixels
+// Color : The color of the glow
+type PieceGlow struct {
+	Radius float64   `json:"radius"`
+	Color  ColorRGBA `json:"color"`
 }
 
 // Images represents settings for Pieces.Type=1, where each piece is stored as its own image
@@ -179,14 +257,42 @@ type AnnotationStyle struct {
 	BorderWidth     int          `json:"border_width"`
 }
 
+// StyledPiece represents a piece that is rendered with a special style.
+// Square : The square of the piece, ex "e4"
+// Piece : Empty to style the piece on the square in the FEN, or a piece code
+// (ex "WN") to render a ghost piece, that is not in the FEN
+// Style : The piece style
+type StyledPiece struct {
+	Square string      `json:"square"`
+	Piece  string      `json:"piece"`
+	Style  *PieceStyle `json:"style"`
+}
+
+// PieceStyle represents how a single piece should be rendered.
+// Opacity : The opacity of the piece, 0 = invisible, 1 = opaque
+// Tint : The color to tint the piece with, where the alpha of the color is the strength of the tint
+// Grayscale : Render the piece in grayscale (before it is tinted)
+type PieceStyle struct {
+	Opacity   float64   `json:"opacity"`
+	Tint      ColorRGBA `json:"tint"`
+	Grayscale bool      `json:"grayscale"`
+}
+
 // Move represents a single move arrow on the chessboard.
 // From : The from position of the move
 // To : The to position of the move
+// Via : Optional squares that the move passes through, between From and To (ex a plan like "a1-a4-h4")
+// Label : An optional label that is drawn on the move (ex "1", "+0.45" or "42%")
+// Weight : An optional weight (0-1) that makes the move thinner and/or more transparent,
+// according to the move style. Not set = no weight (same as 1)
 // Style : The move style (if different from the default style
 type Move struct {
-	From  string     `json:"from"`
-	To    string     `json:"to"`
-	Style *MoveStyle `json:"style"`
+	From   string     `json:"from"`
+	To     string     `json:"to"`
+	Via    []string   `json:"via,omitempty"`
+	Label  string     `json:"label,omitempty"`
+	Weight *float64   `json:"weight,omitempty"`
+	Style  *MoveStyle `json:"style"`
 }
 
 // MoveStyle represents a single move arrow on the chessboard.
@@ -195,12 +301,158 @@ type Move struct {
 // Type : The arrow type, 0=dotted, 1=arrow
 // Factor: The size of the square to use (0.5 equals 50% of square size)
 // Padding: The padding between castling arrows
+// Curve: How much the arrow bends, relative to its length. 0 = straight, positive values bend
+// to the left and negative values to the right, as seen in the direction of the arrow (Type=1)
+// LabelPosition : Where the label is drawn, 0=middle of the move, 1=near the head
+// LabelFontSize : The font size of the label
+// LabelColor : The font color of the label
+// LabelBackgroundColor : The background color of the label
+// WeightWidth : How much a weight of 0 makes the move thinner (0-1, 0 = not at all, 1 = invisible)
+// WeightOpacity : How much a weight of 0 makes the move transparent (0-1, 0 = not at all, 1 = invisible)
+// Outline : An optional outline around the arrow (Type=1)
+// Dash : Optional dash pattern for the arrow shaft, lengths in pixels of alternating dashes and gaps (Type=1)
+// HeadLength : The length of the arrow head, relative to the arrow width. 0 = default (1)
+// HeadWidth : The width of the arrow head, relative to the arrow width. 0 = default (2)
+// HeadType : 0 = filled arrow head, 1 = open arrow head (Type=1)
+// DoubleHead : Draw an arrow head at both ends, for example for exchanges (Type=1)
+// RoundTail : Draw a rounded tail on the arrow (Type=1)
 type MoveStyle struct {
-	Color   ColorRGBA `json:"color"`
-	Color2  ColorRGBA `json:"color2"`
-	Type    MoveType  `json:"type"`
-	Factor  float64   `json:"factor"`
-	Padding float64   `json:"padding"`
+	Color                ColorRGBA         `json:"color"`
+	Color2               ColorRGBA         `json:"color2"`
+	Type                 MoveType          `json:"type"`
+	Factor               float64           `json:"factor"`
+	Padding              float64           `json:"padding"`
+	Curve                float64           `json:"curve"`
+	LabelPosition        MoveLabelPosition `json:"label_position"`
+	LabelFontSize        int               `json:"label_font_size"`
+	LabelColor           ColorRGBA         `json:"label_color"`
+	LabelBackgroundColor ColorRGBA         `json:"label_background_color"`
+	WeightWidth          float64           `json:"weight_width"`
+	WeightOpacity        float64           `json:"weight_opacity"`
+	Outline              *MoveOutline      `json:"outline,omitempty"`
+	Dash                 []float64         `json:"dash,omitempty"`
+	HeadLength           float64           `json:"head_length,omitempty"`
+	HeadWidth            float64           `json:"head_width,omitempty"`
+	HeadType             MoveHeadType      `json:"head_type,omitempty"`
+	DoubleHead           bool              `json:"double_head,omitempty"`
+	RoundTail            bool              `json:"round_tail,omitempty"`
+}
+
+// MoveOutline is an outline around a move arrow.
+// Width : The width of the outline
+// Color : The color of the outline
+type MoveOutline struct {
+	Width float64   `json:"width"`
+	Color ColorRGBA `json:"color"`
+}
+
+// AttackHeatmap represents a heatmap of how many white and black pieces attack each square.
+// Style : The heatmap style (if different from the default style)
+type AttackHeatmap struct {
+	Style *HeatmapStyle `json:"style"`
+}
+
+// SquareValues represents a heatmap of arbitrary values, one value per square.
+// Values : The values, by square (ex "e4")
+// Style : The heatmap style (if different from the default style)
+type SquareValues struct {
+	Values map[string]float64 `json:"values"`
+	Style  *HeatmapStyle      `json:"style"`
+}
+
+// HeatmapStyle represents how a heatmap is painted on the squares.
+// Colormap : The name of the colormap, "diverging", "magma" or "viridis"
+// Colors : An optional custom colormap, from the lowest to the highest value. Used instead of Colormap
+// Opacity : The opacity of the heatmap (0-1)
+// Min : The value that gets the lowest color, lower values are clamped. Not set = the lowest value
+// Max : The value that gets the highest color, higher values are clamped. Not set = the highest value
+// ContestedOnly : Only paint squares that are attacked by both sides (attack heatmap)
+// Legend : Draw a legend in the border to the right of the board
+// LegendFontSize : The font size of the legend values
+// LegendFontColor : The font color of the legend values
+// Labels : Draw the value of each square in the square
+// LabelFontSize : The font size of the square values
+// LabelFormat : The fmt format of the values, in the squares and in the legend, ex "%.2f".
+// Empty = "%+g" for the attack heatmap, and "%.4g" for square values
+type HeatmapStyle struct {
+	Colormap        string      `json:"colormap"`
+	Colors          []ColorRGBA `json:"colors,omitempty"`
+	Opacity         float64     `json:"opacity"`
+	Min             *float64    `json:"min,omitempty"`
+	Max             *float64    `json:"max,omitempty"`
+	ContestedOnly   bool        `json:"contested_only"`
+	Legend          bool        `json:"legend"`
+	LegendFontSize  int         `json:"legend_font_size"`
+	LegendFontColor ColorRGBA   `json:"legend_font_color"`
+	Labels          bool        `json:"labels"`
+	LabelFontSize   int         `json:"label_font_size"`
+	LabelFormat     string      `json:"label_format"`
+}
+
+// TacticalHints represents the tactical hints of a position, see ImageContext.ShowTactics.
+// Style : The tactics style (if different from the default style)
+type TacticalHints struct {
+	Style *TacticsStyle `json:"style"`
+}
+
+// TacticsStyle represents which tactical hints are shown, and how they are rendered.
+// Hanging : Highlight the pieces that are attacked and not defended
+// Pins : Draw a line from a pinning piece, through the pinned piece, to the king
+// DiscoveredAttacks : Draw a line from a sliding piece, through a piece of the same color, to the
+// piece that would be attacked if that piece moved away
+// Forks : Draw arrows from a piece that attacks two or more pieces, that are more valuable or not defended
+// HangingStyle : The highlight style of hanging pieces
+// PinStyle : The move style of pins
+// DiscoveredAttackStyle : The move style of discovered attacks
+// ForkStyle : The move style of forks
+type TacticsStyle struct {
+	Hanging               bool           `json:"hanging"`
+	Pins                  bool           `json:"pins"`
+	DiscoveredAttacks     bool           `json:"discovered_attacks"`
+	Forks                 bool           `json:"forks"`
+	HangingStyle          HighlightStyle `json:"hanging_style"`
+	PinStyle              MoveStyle      `json:"pin_style"`
+	DiscoveredAttackStyle MoveStyle      `json:"discovered_attack_style"`
+	ForkStyle             MoveStyle      `json:"fork_style"`
+}
+
+// LegalMoves represents the legal moves of a piece, see ImageContext.ShowLegalMoves.
+// Square : The square of the piece (ex "g1")
+// Style : The legal moves style (if different from the default style)
+type LegalMoves struct {
+	Square string           `json:"square"`
+	Style  *LegalMovesStyle `json:"style"`
+}
+
+// LegalMovesStyle represents how the legal moves of a piece are rendered.
+// SelectedStyle : The highlight style of the square of the piece
+// MoveStyle : The highlight style of the empty squares that the piece can move to
+// CaptureStyle : The highlight style of the squares with pieces that the piece can capture
+type LegalMovesStyle struct {
+	SelectedStyle HighlightStyle `json:"selected_style"`
+	MoveStyle     HighlightStyle `json:"move_style"`
+	CaptureStyle  HighlightStyle `json:"capture_style"`
+}
+
+// LastMove represents the last move, inferred from the previous position, see
+// ImageContext.AddLastMoveFromPositions.
+// PreviousFen : The FEN string of the position before the move
+// Style : The last move style (if different from the default style)
+type LastMove struct {
+	PreviousFen string         `json:"previous_fen"`
+	Style       *LastMoveStyle `json:"style"`
+}
+
+// LastMoveStyle represents how the last move is rendered.
+// FromStyle : The highlight style of the square that the piece moved from
+// ToStyle : The highlight style of the square that the piece moved to
+// Arrow : Should an arrow be drawn from the from square to the to square
+// ArrowStyle : The move style of the arrow
+type LastMoveStyle struct {
+	FromStyle  HighlightStyle `json:"from_style"`
+	ToStyle    HighlightStyle `json:"to_style"`
+	Arrow      bool           `json:"arrow"`
+	ArrowStyle MoveStyle      `json:"arrow_style"`
 }
 
 // FontStyle : Font to use, if path is not specified (or does not exist),
@@ -0,0 +1,103 @@
+package chessImager
+
+import (
+	"math"
+	"testing"
+)
+
+// knightTable is a piece-square table for knights, from a1 to h8
+var knightTable = []float64{
+	-50, -40, -30, -30, -30, -30, -40, -50,
+	-40, -20, 0, 5, 5, 0, -20, -40,
+	-30, 5, 10, 15, 15, 10, 5, -30,
+	-30, 0, 15, 20, 20, 15, 0, -30,
+	-30, 5, 15, 20, 20, 15, 5, -30,
+	-30, 0, 10, 15, 15, 10, 0, -30,
+	-40, -20, 0, 0, 0, 0, -20, -40,
+	-50, -40, -30, -30, -30, -30, -40, -50,
+}
+
+func knightValues() map[string]float64 {
+	values := map[string]float64{}
+	for n, value := range knightTable {
+		values[string([]byte{byte('a' + n%8), byte('1' + n/8)})] = value
+	}
+
+	return values
+}
+
+func TestSquareValues(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+	style, err := ctx.NewHeatmapStyle("viridis", 0.9, true)
+	if err != nil {
+		t.Fatalf("NewHeatmapStyle() failed : %v", err)
+	}
+	style.Labels = true
+	ctx.SetSquareValuesWithStyle(knightValues(), style)
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "squareValues.png", &img)
+}
+
+func TestSquareValuesClamped(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	const fen = "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"
+	ctx := imager.NewContext(fen)
+	style, err := ctx.NewHeatmapStyle("magma", 0.6, true)
+	if err != nil {
+		t.Fatalf("NewHeatmapStyle() failed : %v", err)
+	}
+	// Move frequencies in percent, where everything above 30% gets the highest color
+	low, high := 0.0, 30.0
+	style.Min, style.Max = &low, &high
+	style.Labels = true
+	style.LabelFormat = "%.0f%%"
+	ctx.SetSquareValuesWithStyle(map[string]float64{"e5": 45, "c5": 25, "e6": 12, "c6": 8, "d5": 5}, style)
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "squareValuesClamped.png", &img)
+}
+
+func TestSquareValuesInvalidSquare(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").SetSquareValues(map[string]float64{"e9": 1})
+	if _, err := imager.RenderWithContext(ctx); err == nil {
+		t.Errorf("RenderWithContext() expected an error for an invalid square")
+	}
+}
+
+func TestSquareValuesInvalidValue(t *testing.T) {
+	t.Parallel()
+
+	low, high := 0.0, 1.0
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		for _, clamped := range []bool{false, true} {
+			imager := NewImager()
+			ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1")
+			style, err := ctx.NewHeatmapStyle("viridis", 0.9, false)
+			if err != nil {
+				t.Fatalf("NewHeatmapStyle() failed : %v", err)
+			}
+			if clamped {
+				style.Min, style.Max = &low, &high
+			}
+			ctx.SetSquareValuesWithStyle(map[string]float64{"e4": value, "d4": 0.5}, style)
+			if _, err = imager.RenderWithContext(ctx); err == nil {
+				t.Errorf("RenderWithContext() expected an error for the value %v (clamped=%v)", value, clamped)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package chessImager
+
+import (
+	"slices"
+)
+
+// pieceValues are the values of the pieces, used to find forks
+var pieceValues = map[chessPiece]int{
+	whitePawn:   1,
+	whiteKnight: 3,
+	whiteBishop: 3,
+	whiteRook:   5,
+	whiteQueen:  9,
+	whiteKing:   100,
+}
+
+// tacticalLine is a line from a piece, through another piece, to a target. It is used
+// for pins (pinner, pinned piece, king) and discovered attacks (attacker, the piece
+// that can move away, target).
+type tacticalLine struct {
+	from, through, to square
+}
+
+// fork is a piece that attacks two or more pieces at the same time.
+type fork struct {
+	from    square
+	targets []square
+}
+
+// pieces returns the squares of all pieces of one side, from a1 to h8.
+func (p *position) pieces(white bool) []square {
+	var result []square
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if piece := p.board[x][y]; piece != noPiece && isWhite(piece) == white {
+				result = append(result, square{x, y})
+			}
+		}
+	}
+
+	return result
+}
+
+// hangingPieces returns the pieces of both sides that are attacked, and not defended.
+// Kings are never hanging.
+func (p *position) hangingPieces() []square {
+	var result []square
+	for _, white := range []bool{true, false} {
+		for _, s := range p.pieces(white) {
+			if pieceKind(p.pieceAt(s)) == whiteKing {
+				continue
+			}
+			if len(p.attackers(s, !white)) > 0 && len(p.attackers(s, white)) == 0 {
+				result = append(result, s)
+			}
+		}
+	}
+
+	return result
+}
+
+// pins returns the absolute pins of both sides, from the pinning piece, through
+// the pinned piece, to the king.
+func (p *position) pins() []tacticalLine {
+	var result []tacticalLine
+	for _, white := range []bool{true, false} {
+		for _, king := range p.pieces(white) {
+			if pieceKind(p.pieceAt(king)) != whiteKing {
+				continue
+			}
+			// Look from the king, for an own piece and then an enemy piece that slides along the line
+			for _, line := range p.xrays(king) {
+				pinned, pinner := p.pieceAt(line.through), p.pieceAt(line.to)
+				if isWhite(pinned) == white && isWhite(pinner) != white && slidesAlong(pinner, line.to, king) {
+					result = append(result, tacticalLine{from: line.to, through: line.through, to: king})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// discoveredAttacks returns the lines of both sides, where a sliding piece would attack an
+// enemy piece (not a pawn), if a piece of its own color that is in the way moves away.
+func (p *position) discoveredAttacks() []tacticalLine {
+	var result []tacticalLine
+	for _, white := range []bool{true, false} {
+		for _, from := range p.pieces(white) {
+			for _, line := range p.xrays(from) {
+				blocker, target := p.pieceAt(line.through), p.pieceAt(line.to)
+				if isWhite(blocker) == white && isWhite(target) != white && pieceKind(target) != whitePawn &&
+					slidesAlong(p.pieceAt(from), from, line.to) {
+					result = append(result, line)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// forks returns the pieces of both sides that attack two or more enemy pieces, that are
+// either more valuable than the attacking piece, or not defended.
+func (p *position) forks() []fork {
+	var result []fork
+	for _, white := range []bool{true, false} {
+		for _, from := range p.pieces(white) {
+			value := pieceValues[pieceKind(p.pieceAt(from))]
+			f := fork{from: from}
+			for _, target := range p.pieces(!white) {
+				if !slices.Contains(p.attackers(target, white), from) {
+					continue
+				}
+				if pieceValues[pieceKind(p.pieceAt(target))] > value || len(p.attackers(target, !white)) == 0 {
+					f.targets = append(f.targets, target)
+				}
+			}
+			if len(f.targets) >= 2 {
+				result = append(result, f)
+			}
+		}
+	}
+
+	return result
+}
+
+// xrays returns, for each of the eight directions from a square, the first two pieces
+// that are found in that direction, if there are two pieces.
+func (p *position) xrays(from square) []tacticalLine {
+	var result []tacticalLine
+	for _, dir := range queenDirs {
+		var found []square
+		s := square{from.x + dir.x, from.y + dir.y}
+		for s.onBoard() && len(found) < 2 {
+			if p.pieceAt(s) != noPiece {
+				found = append(found, s)
+			}
+			s = square{s.x + dir.x, s.y + dir.y}
+		}
+		if len(found) == 2 {
+			result = append(result, tacticalLine{from: from, through: found[0], to: found[1]})
+		}
+	}
+
+	return result
+}
+
+// slidesAlong returns true if the piece on from can attack along the line to to,
+// that must be a straight or diagonal line, if there is nothing in between.
+func slidesAlong(piece chessPiece, from, to square) bool {
+	straight := from.x == to.x || from.y == to.y
+	switch pieceKind(piece) {
+	case whiteQueen:
+		return true
+	case whiteRook:
+		return straight
+	case whiteBishop:
+		return !straight
+	default:
+		return false
+	}
+}
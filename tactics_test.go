@@ -0,0 +1,76 @@
+package chessImager
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// tacticsFen has a knight fork, a pin, a discovered attack and three hanging pieces
+const tacticsFen = "r3k3/2Nn1ppp/8/1B2q3/7b/4N3/PPP5/1K2R2R w - - 0 1"
+
+func Test_tactics(t *testing.T) {
+	t.Parallel()
+
+	pos, err := newPosition(tacticsFen)
+	if err != nil {
+		t.Fatalf("newPosition() failed : %v", err)
+	}
+
+	var pins, discovered, forks []string
+	for _, l := range pos.pins() {
+		pins = append(pins, fmt.Sprintf("%v-%v-%v", l.from, l.through, l.to))
+	}
+	for _, l := range pos.discoveredAttacks() {
+		discovered = append(discovered, fmt.Sprintf("%v-%v-%v", l.from, l.through, l.to))
+	}
+	for _, f := range pos.forks() {
+		forks = append(forks, fmt.Sprintf("%v:%v", f.from, f.targets))
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"hanging", fmt.Sprint(pos.hangingPieces()), "[c7 a8 h4]"},
+		{"pins", pins, []string{"b5-d7-e8"}},
+		{"discovered attacks", discovered, []string{"e1-e3-e5"}},
+		{"forks", forks, []string{"c7:[a8 e8]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTactics(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(tacticsFen).ShowTactics()
+
+	img, err := imager.RenderWithContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "tactics.png", &img)
+}
+
+func TestTacticsToggled(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext(tacticsFen).
+		ShowTactics().
+		AddSettingsOverlay(`{"tactics_style": {"hanging": false, "forks": false}}`)
+
+	img, err := imager.RenderWithContextInverted(ctx)
+	if err != nil {
+		t.Fatalf("Failed to render chess board: %v", err)
+	}
+	compareImages(t, "tacticsToggled.png", &img)
+}
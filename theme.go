@@ -0,0 +1,130 @@
+package chessImager
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultTheme is the name of the theme that is defined by the embedded config/default.json file.
+const DefaultTheme = "default"
+
+//go:embed config/themes/*.json
+var themeFiles embed.FS
+
+// themeAssets contains the assets of the bundled themes, like the bold piece set
+//
+//go:embed config/themes/*.png
+var themeAssets embed.FS
+
+// themeAssetDir is the directory of the bundled theme assets, inside themeAssets
+const themeAssetDir = "config/themes"
+
+var (
+	themesMu sync.RWMutex
+	// themes contains the JSON documents of all themes, by name
+	themes = loadEmbeddedThemes()
+)
+
+// loadEmbeddedThemes loads the themes that are bundled with the package.
+func loadEmbeddedThemes() map[string][]byte {
+	result := map[string][]byte{DefaultTheme: []byte(defaultSettings)}
+
+	entries, _ := themeFiles.ReadDir("config/themes")
+	for _, entry := range entries {
+		// We ignore the error here, since the embedded files should always be readable.
+		data, _ := themeFiles.ReadFile(path.Join("config/themes", entry.Name()))
+		result[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = data
+	}
+
+	return result
+}
+
+// Themes returns the names of all available themes, sorted by name. This includes the
+// bundled themes ("default", "brown", "blue", "green", "tournament", "newspaper-bw" and
+// "high-contrast") and all themes that have been added with RegisterTheme.
+func Themes() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RegisterTheme adds a theme to the theme registry, so that it can be used with
+// Imager.UseTheme. Registering a theme with an existing name replaces that theme.
+// The settings are copied, so later changes to them do not affect the theme.
+// The settings are validated, except for asset files, that are checked by UseTheme,
+// since they are loaded by the imager that uses the theme.
+func RegisterTheme(name string, settings *Settings) error {
+	if name == "" {
+		return errors.New("theme name can not be empty")
+	}
+	if settings == nil {
+		return errors.New("theme settings can not be nil")
+	}
+	if err := settings.validate(func(string) bool { return true }); err != nil {
+		return fmt.Errorf("failed to register theme %q : %w", name, err)
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to register theme %q : %v", name, err)
+	}
+
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[name] = data
+
+	return nil
+}
+
+// loadTheme returns a new copy of the settings for a theme.
+func loadTheme(name string) (*Settings, error) {
+	themesMu.RLock()
+	data, ok := themes[name]
+	themesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown theme : %q", name)
+	}
+
+	s, err := loadDefaultSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.merge(bytes.NewReader(data))
+}
+
+// UseTheme replaces the current settings with the settings of a theme, on top of the
+// default settings. The "newspaper-bw" and "high-contrast" themes use a bold piece set
+// that is bundled with the package, the other bundled themes use the embedded pieces.
+// Use ApplySettings afterwards to override parts of the theme, like the piece set.
+// Use the Themes function to list the available themes. The settings of the theme
+// are validated, see Settings.Validate.
+func (i *Imager) UseTheme(name string) error {
+	s, err := loadTheme(name)
+	if err != nil {
+		return err
+	}
+	c := *i
+	c.settings = s
+	if err = s.validate(c.assetExists); err != nil {
+		return err
+	}
+
+	i.settings = s
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package chessImager
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestThemes(t *testing.T) {
+	t.Parallel()
+
+	names := Themes()
+	for _, want := range []string{"blue", "brown", "default", "green", "high-contrast", "newspaper-bw", "tournament"} {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("Themes() is missing theme %q", want)
+		}
+	}
+}
+
+func TestUseTheme(t *testing.T) {
+	t.Parallel()
+
+	const fen = "b2r3r/k3Rp1p/p2q1np1/Np1P4/3p1Q2/P4PPB/1PP4P/1K6 b - - 1 25"
+
+	for _, name := range Themes() {
+		t.Run(name, func(t *testing.T) {
+			imager := NewImager()
+			err := imager.UseTheme(name)
+			if err != nil {
+				t.Fatalf("UseTheme() failed : %v", err)
+			}
+
+			ctx := imager.NewContext(fen).AddHighlight("e7").AddAnnotation("e7", "!!").AddMove("e1", "e7")
+			_, err = imager.RenderWithContext(ctx)
+			if err != nil {
+				t.Errorf("failed to render theme %q : %v", name, err)
+			}
+		})
+	}
+}
+
+func TestUseThemeBrown(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	if err := imager.UseTheme("brown"); err != nil {
+		t.Fatalf("UseTheme() failed : %v", err)
+	}
+
+	img, err := imager.Render("8/8/8/8/8/8/8/8 w - - 0 1")
+	if err != nil {
+		t.Fatalf("failed to render : %v", err)
+	}
+
+	// a1 is a dark square
+	r := imager.SquareRect(nil, "a1", false)
+	got := color.RGBAModel.Convert(img.At(r.Min.X+5, r.Min.Y+5))
+	if want := (color.RGBA{R: 0xB5, G: 0x88, B: 0x63, A: 0xFF}); got != want {
+		t.Errorf("dark square got color = %v, want %v", got, want)
+	}
+}
+
+func TestUseThemePieces(t *testing.T) {
+	t.Parallel()
+
+	filename := "themeNewspaperPieces.png"
+
+	// The bold pieces are bundled with the package, so they are also found by
+	// imagers that load their assets from another file system
+	for _, imager := range []*Imager{NewImager(), NewImager(WithFS(fstest.MapFS{}))} {
+		if err := imager.UseTheme("newspaper-bw"); err != nil {
+			t.Fatalf("UseTheme() failed : %v", err)
+		}
+
+		img, err := imager.Render("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
+		if err != nil {
+			t.Fatalf("Failed to render chess board: %v", err)
+		}
+
+		compareImages(t, filename, &img)
+	}
+}
+
+func TestUseThemeUnknown(t *testing.T) {
+	t.Parallel()
+
+	if err := NewImager().UseTheme("no such theme"); err == nil {
+		t.Errorf("UseTheme() with unknown theme returned no error")
+	}
+}
+
+func TestRegisterTheme(t *testing.T) {
+	t.Parallel()
+
+	s := NewImager().settings
+	s.Border.Width = 12
+	err := RegisterTheme("test-register", s)
+	if err != nil {
+		t.Fatalf("RegisterTheme() failed : %v", err)
+	}
+
+	// Changes after registering should not affect the theme
+	s.Border.Width = 99
+
+	imager := NewImager()
+	if err = imager.UseTheme("test-register"); err != nil {
+		t.Fatalf("UseTheme() failed : %v", err)
+	}
+	if imager.settings.Border.Width != 12 {
+		t.Errorf("registered theme got border width = %v, want 12", imager.settings.Border.Width)
+	}
+	s.Border.Width = 12
+	if !reflect.DeepEqual(imager.settings, s) {
+		t.Errorf("registered theme got = %v, want %v", imager.settings, s)
+	}
+
+	if err = RegisterTheme("", s); err == nil {
+		t.Errorf("RegisterTheme() with empty name returned no error")
+	}
+	if err = RegisterTheme("test-nil", nil); err == nil {
+		t.Errorf("RegisterTheme() with nil settings returned no error")
+	}
+}
+
+func TestRegisterThemeInvalid(t *testing.T) {
+	t.Parallel()
+
+	s := NewImager().settings
+	s.Board.Default.Size = 0
+	if err := RegisterTheme("test-invalid", s); err == nil {
+		t.Errorf("RegisterTheme() with invalid settings returned no error")
+	}
+	if err := NewImager().UseTheme("test-invalid"); err == nil {
+		t.Errorf("UseTheme() of a theme that was not registered returned no error")
+	}
+}
+
+// TestUseThemeMissingAsset is not parallel, since the registered theme is invalid, and
+// would make the tests that use all themes fail
+func TestUseThemeMissingAsset(t *testing.T) {
+	t.Cleanup(func() {
+		themesMu.Lock()
+		defer themesMu.Unlock()
+		delete(themes, "test-missing-asset")
+	})
+
+	s := NewImager().settings
+	s.Board.Type = boardTypeImage
+	s.Board.Image.Path = "test/data/missing.jpg"
+	s.Board.Image.Rect = Rectangle{Width: 600, Height: 600}
+	if err := RegisterTheme("test-missing-asset", s); err != nil {
+		t.Fatalf("RegisterTheme() failed : %v", err)
+	}
+
+	imager := NewImager()
+	if err := imager.UseTheme("test-missing-asset"); err == nil {
+		t.Errorf("UseTheme() with a missing asset returned no error")
+	}
+	if imager.settings.Board.Type != boardTypeDefault {
+		t.Errorf("UseTheme() with a missing asset changed the settings")
+	}
+}
@@ -0,0 +1,316 @@
+package chessImager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError contains all the problems that were found when validating settings.
+// Each problem starts with the JSON path of the invalid field, for example:
+//
+//	pieces.image_map.pieces[3].piece: unknown piece "WX"
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid settings :\n\t" + strings.Join(e.Problems, "\n\t")
+}
+
+// validator collects validation problems
+type validator struct {
+	problems []string
+	// exists reports if an asset file exists
+	exists func(string) bool
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) positive(path string, value float64) {
+	if value <= 0 {
+		v.addf(path, "must be positive, got %v", value)
+	}
+}
+
+func (v *validator) notNegative(path string, value float64) {
+	if value < 0 {
+		v.addf(path, "can not be negative, got %v", value)
+	}
+}
+
+func (v *validator) enum(path string, value, max int) {
+	if value < 0 || value > max {
+		v.addf(path, "must be between 0 and %d, got %d", max, value)
+	}
+}
+
+func (v *validator) fraction(path string, value float64) {
+	if value < 0 || value > 1 {
+		v.addf(path, "must be between 0 and 1, got %v", value)
+	}
+}
+
+func (v *validator) file(path, file string) {
+	if file == "" {
+		v.addf(path, "missing path")
+		return
+	}
+	if !v.exists(file) {
+		v.addf(path, "file not found %q", file)
+	}
+}
+
+// Validate checks the settings, and returns a *ValidationError containing all the
+// problems found, or nil if the settings are valid. Settings that are not used,
+// like image paths for piece types that are not selected, are not validated.
+// Asset paths are checked relative to the working directory, and in the assets of
+// the bundled themes.
+func (s *Settings) Validate() error {
+	return s.validate((&Imager{}).assetExists)
+}
+
+// validate validates the settings, using exists to check if asset files exist.
+func (s *Settings) validate(exists func(string) bool) error {
+	v := &validator{exists: exists}
+
+	if err := (&Imager{}).validateOrder(s.Order); err != nil {
+		v.addf("order", "%v", err)
+	}
+
+	v.notNegative("border.width", float64(s.Border.Width))
+
+	v.enum("board.type", int(s.Board.Type), int(boardTypeImage))
+	switch s.Board.Type {
+	case boardTypeDefault:
+		v.positive("board.default.size", float64(s.Board.Default.Size))
+		v.fill("board.default.white_fill", s.Board.Default.WhiteFill)
+		v.fill("board.default.black_fill", s.Board.Default.BlackFill)
+		v.fill("border.fill", s.Border.Fill)
+	case boardTypeImage:
+		v.file("board.image.path", s.Board.Image.Path)
+		v.rect("board.image.rect", s.Board.Image.Rect)
+	}
+
+	v.enum("rank_and_file.type", int(s.RankAndFile.Type), int(rankAndFileTypeInSquares))
+	if s.RankAndFile.Type != rankAndFileTypeNone {
+		v.positive("rank_and_file.font_size", float64(s.RankAndFile.FontSize))
+	}
+
+	v.positive("pieces.factor", s.Pieces.Factor)
+	v.enum("pieces.type", int(s.Pieces.Type), int(piecesTypeImageMap))
+	switch s.Pieces.Type {
+	case piecesTypeImages:
+		seen := map[chessPiece]bool{}
+		for n, piece := range s.Pieces.Images.Pieces {
+			path := fmt.Sprintf("pieces.images.pieces[%d]", n)
+			v.piece(path+".piece", piece.Piece, seen)
+			v.file(path+".path", piece.Path)
+		}
+	case piecesTypeImageMap:
+		v.file("pieces.image_map.path", s.Pieces.ImageMap.Path)
+		seen := map[chessPiece]bool{}
+		for n, piece := range s.Pieces.ImageMap.Pieces {
+			path := fmt.Sprintf("pieces.image_map.pieces[%d]", n)
+			v.piece(path+".piece", piece.Piece, seen)
+			v.rect(path+".rect", piece.Rect)
+		}
+	}
+
+	v.pieceEffects("pieces.white_effects", s.Pieces.WhiteEffects)
+	v.pieceEffects("pieces.black_effects", s.Pieces.BlackEffects)
+
+	if s.FontStyle.Path != "" {
+		v.file("font_style.path", s.FontStyle.Path)
+	}
+
+	v.highlightStyle("highlight_style", &s.HighlightStyle)
+	v.annotationStyle("annotation_style", &s.AnnotationStyle)
+	v.moveStyle("move_style", &s.MoveStyle, false)
+	v.heatmapStyle("heatmap_style", &s.HeatmapStyle, false)
+	v.tacticsStyle("tactics_style", &s.TacticsStyle, false)
+	v.legalMovesStyle("legal_moves_style", &s.LegalMovesStyle)
+	v.lastMoveStyle("last_move_style", &s.LastMoveStyle, false)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validateStyles validates the heatmap, tactics, legal moves and last move styles that
+// are given to the image context, and returns a *ValidationError containing all the
+// problems found, or nil if the styles are valid.
+func (c *ImageContext) validateStyles() error {
+	v := &validator{}
+
+	if c.AttackHeatmap != nil && c.AttackHeatmap.Style != nil {
+		v.heatmapStyle("attack_heatmap.style", c.AttackHeatmap.Style, true)
+	}
+	if c.SquareValues != nil && c.SquareValues.Style != nil {
+		v.heatmapStyle("square_values.style", c.SquareValues.Style, true)
+	}
+	if c.TacticalHints != nil && c.TacticalHints.Style != nil {
+		v.tacticsStyle("tactical_hints.style", c.TacticalHints.Style, true)
+	}
+	if c.LegalMoves != nil && c.LegalMoves.Style != nil {
+		v.legalMovesStyle("legal_moves.style", c.LegalMoves.Style)
+	}
+	if c.LastMove != nil && c.LastMove.Style != nil {
+		v.lastMoveStyle("last_move.style", c.LastMove.Style, true)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) fill(path string, f *Fill) {
+	if f == nil {
+		return
+	}
+
+	v.enum(path+".type", int(f.Type), int(fillTypeNoise))
+	switch f.Type {
+	case fillTypeLinearGradient, fillTypeRadialGradient:
+		if len(f.Colors) < 2 {
+			v.addf(path+".colors", "a gradient needs at least 2 colors, got %d", len(f.Colors))
+		}
+	case fillTypeTexture:
+		v.file(path+".path", f.Path)
+	case fillTypeNoise:
+		if len(f.Colors) != 2 {
+			v.addf(path+".colors", "noise needs 2 colors, got %d", len(f.Colors))
+		}
+		v.notNegative(path+".size", f.Size)
+	}
+}
+
+func (v *validator) pieceEffects(path string, e *PieceEffects) {
+	if e == nil {
+		return
+	}
+	if e.Shadow != nil {
+		v.notNegative(path+".shadow.blur", e.Shadow.Blur)
+	}
+	if e.Outline != nil {
+		v.positive(path+".outline.width", e.Outline.Width)
+	}
+	if e.Glow != nil {
+		v.positive(path+".glow.radius", e.Glow.Radius)
+	}
+}
+
+func (v *validator) rect(path string, r Rectangle) {
+	v.notNegative(path+".x", r.X)
+	v.notNegative(path+".y", r.Y)
+	v.positive(path+".width", r.Width)
+	v.positive(path+".height", r.Height)
+}
+
+func (v *validator) piece(path, code string, seen map[chessPiece]bool) {
+	p, ok := code2Piece[strings.ToUpper(code)]
+	switch {
+	case !ok:
+		v.addf(path, "unknown piece %q", code)
+	case seen[p]:
+		v.addf(path, "duplicate piece %q", code)
+	default:
+		seen[p] = true
+	}
+}
+
+func (v *validator) highlightStyle(path string, s *HighlightStyle) {
+	v.enum(path+".type", int(s.Type), int(HighlightTypeX))
+	v.notNegative(path+".width", float64(s.Width))
+	switch s.Type {
+	case HighlightTypeCircle, HighlightTypeFilledCircle, HighlightTypeX:
+		v.positive(path+".factor", s.Factor)
+	}
+}
+
+func (v *validator) annotationStyle(path string, s *AnnotationStyle) {
+	v.enum(path+".position", int(s.Position), int(PositionTypeMiddle))
+	v.positive(path+".size", float64(s.Size))
+	v.positive(path+".font_size", float64(s.FontSize))
+	v.notNegative(path+".border_width", float64(s.BorderWidth))
+}
+
+// moveStyle validates a move style. If inherited is true, a label font size that is zero is
+// taken from the settings when rendering, like for the styles of the image context.
+func (v *validator) moveStyle(path string, s *MoveStyle, inherited bool) {
+	fontSize := v.positive
+	if inherited {
+		fontSize = v.notNegative
+	}
+
+	v.enum(path+".type", int(s.Type), int(MoveTypeArrow))
+	v.positive(path+".factor", s.Factor)
+	v.enum(path+".label_position", int(s.LabelPosition), int(MoveLabelPositionHead))
+	fontSize(path+".label_font_size", float64(s.LabelFontSize))
+	v.fraction(path+".weight_width", s.WeightWidth)
+	v.fraction(path+".weight_opacity", s.WeightOpacity)
+	if s.Outline != nil {
+		v.positive(path+".outline.width", s.Outline.Width)
+	}
+	var dash float64
+	for n, d := range s.Dash {
+		v.notNegative(fmt.Sprintf("%s.dash[%d]", path, n), d)
+		dash += d
+	}
+	if len(s.Dash) > 0 && dash <= 0 {
+		v.addf(path+".dash", "must contain a positive length")
+	}
+	v.notNegative(path+".head_length", s.HeadLength)
+	v.notNegative(path+".head_width", s.HeadWidth)
+	v.enum(path+".head_type", int(s.HeadType), int(MoveHeadTypeOpen))
+}
+
+// heatmapStyle validates a heatmap style. If inherited is true, font sizes that are zero are
+// taken from the settings when rendering, like for the styles of the image context.
+func (v *validator) heatmapStyle(path string, s *HeatmapStyle, inherited bool) {
+	fontSize := v.positive
+	if inherited {
+		fontSize = v.notNegative
+	}
+
+	if len(s.Colors) == 0 {
+		if _, err := s.getColormap(); err != nil {
+			v.addf(path+".colormap", "%v", err)
+		}
+	}
+	if len(s.Colors) == 1 {
+		v.addf(path+".colors", "a colormap needs at least 2 colors, got 1")
+	}
+	v.fraction(path+".opacity", s.Opacity)
+	if s.Min != nil && s.Max != nil && *s.Min >= *s.Max {
+		v.addf(path+".max", "must be larger than min, got %v <= %v", *s.Max, *s.Min)
+	}
+	if s.Legend {
+		fontSize(path+".legend_font_size", float64(s.LegendFontSize))
+	}
+	if s.Labels {
+		fontSize(path+".label_font_size", float64(s.LabelFontSize))
+	}
+}
+
+func (v *validator) tacticsStyle(path string, s *TacticsStyle, inherited bool) {
+	v.highlightStyle(path+".hanging_style", &s.HangingStyle)
+	v.moveStyle(path+".pin_style", &s.PinStyle, inherited)
+	v.moveStyle(path+".discovered_attack_style", &s.DiscoveredAttackStyle, inherited)
+	v.moveStyle(path+".fork_style", &s.ForkStyle, inherited)
+}
+
+func (v *validator) legalMovesStyle(path string, s *LegalMovesStyle) {
+	v.highlightStyle(path+".selected_style", &s.SelectedStyle)
+	v.highlightStyle(path+".move_style", &s.MoveStyle)
+	v.highlightStyle(path+".capture_style", &s.CaptureStyle)
+}
+
+func (v *validator) lastMoveStyle(path string, s *LastMoveStyle, inherited bool) {
+	v.highlightStyle(path+".from_style", &s.FromStyle)
+	v.highlightStyle(path+".to_style", &s.ToStyle)
+	v.moveStyle(path+".arrow_style", &s.ArrowStyle, inherited)
+}
@@ -0,0 +1,191 @@
+package chessImager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateThemes(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range Themes() {
+		s, err := loadTheme(name)
+		if err != nil {
+			t.Fatalf("failed to load theme %q : %v", name, err)
+		}
+		if err = s.Validate(); err != nil {
+			t.Errorf("theme %q is invalid : %v", name, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	s := NewImager().settings
+	s.Board.Default.Size = -600
+	s.Pieces.Factor = 0
+	s.Pieces.Type = piecesTypeImageMap
+	s.Pieces.ImageMap.Path = "test/data/pieces_colorful.png"
+	for n := range s.Pieces.ImageMap.Pieces {
+		s.Pieces.ImageMap.Pieces[n].Rect = Rectangle{Width: 128, Height: 128}
+	}
+	s.Pieces.ImageMap.Pieces[3].Piece = "WX"
+	s.Pieces.ImageMap.Pieces[4].Piece = "wp"
+	s.HighlightStyle.Type = 7
+	s.MoveStyle.Type = -1
+	s.HeatmapStyle.Colormap = "rainbow"
+
+	err := s.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate() got = %v, want *ValidationError", err)
+	}
+
+	want := []string{
+		"board.default.size: must be positive, got -600",
+		"pieces.factor: must be positive, got 0",
+		`pieces.image_map.pieces[3].piece: unknown piece "WX"`,
+		`pieces.image_map.pieces[4].piece: duplicate piece "wp"`,
+		"highlight_style.type: must be between 0 and 4, got 7",
+		"move_style.type: must be between 0 and 1, got -1",
+		`heatmap_style.colormap: unknown colormap : "rainbow"`,
+	}
+	if strings.Join(ve.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Validate() got problems:\n%v\nwant:\n%v", strings.Join(ve.Problems, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateMissingFiles(t *testing.T) {
+	t.Parallel()
+
+	s := NewImager().settings
+	s.Board.Type = boardTypeImage
+	s.Board.Image.Path = "test/data/missing.jpg"
+	s.FontStyle.Path = "test/data/missing.ttf"
+
+	err := s.Validate()
+	if err == nil {
+		t.Fatalf("Validate() with missing files returned no error")
+	}
+	for _, want := range []string{`board.image.path: file not found "test/data/missing.jpg"`,
+		`font_style.path: file not found "test/data/missing.ttf"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error is missing %q : %v", want, err)
+		}
+	}
+}
+
+func TestLoadSettingsUnknownKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "unknown.json")
+	err := os.WriteFile(path, []byte(`{"border": {"colour": "#123456"}}`), 0600)
+	if err != nil {
+		t.Fatalf("failed to write settings file : %v", err)
+	}
+
+	_, err = NewImagerFromPath(path)
+	if err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("unknown key got error = %v", err)
+	}
+}
+
+func TestLoadSettingsInvalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	err := os.WriteFile(path, []byte(`{"pieces": {"factor": 0}}`), 0600)
+	if err != nil {
+		t.Fatalf("failed to write settings file : %v", err)
+	}
+
+	imager := NewImager()
+	if err = imager.LoadSettings(path); err == nil {
+		t.Errorf("LoadSettings() with invalid settings returned no error")
+	}
+	if imager.settings.Pieces.Factor != 1 {
+		t.Errorf("LoadSettings() changed the settings, even though they were invalid")
+	}
+}
+
+func TestValidateOverlay(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	ctx := imager.NewContext("8/8/8/8/8/8/8/8 w - - 0 1").AddSettingsOverlay(`{"border": {"fill": {"type": 3}}}`)
+
+	_, err := imager.RenderWithContext(ctx)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("RenderWithContext() got = %v, want *ValidationError", err)
+	}
+	if want := "border.fill.colors: noise needs 2 colors, got 0"; !slices.Contains(ve.Problems, want) {
+		t.Errorf("RenderWithContext() got problems %v, want %q", ve.Problems, want)
+	}
+}
+
+func TestValidateContextStyles(t *testing.T) {
+	t.Parallel()
+
+	imager := NewImager()
+	tactics := imager.settings.TacticsStyle
+	tactics.PinStyle.Type = 5
+	heatmap := imager.settings.HeatmapStyle
+	heatmap.Opacity = 2
+	legalMoves := imager.settings.LegalMovesStyle
+	legalMoves.MoveStyle.Width = -1
+	lastMove := imager.settings.LastMoveStyle
+	lastMove.ArrowStyle.Factor = 0
+
+	ctx := imager.NewContext("r3k3/2Nn1ppp/8/1B2q3/7b/4N3/PPP5/1K2R2R w - - 0 1").
+		ShowTacticsWithStyle(&tactics).
+		ShowAttackHeatmapWithStyle(&heatmap).
+		ShowLegalMovesWithStyle("c7", &legalMoves).
+		AddLastMoveFromPositionsWithStyle("r3k3/3n1ppp/1N6/1B2q3/7b/4N3/PPP5/1K2R2R b - - 0 1", &lastMove)
+
+	_, err := imager.RenderWithContext(ctx)
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("RenderWithContext() got = %v, want *ValidationError", err)
+	}
+
+	want := []string{
+		"attack_heatmap.style.opacity: must be between 0 and 1, got 2",
+		"tactical_hints.style.pin_style.type: must be between 0 and 1, got 5",
+		"legal_moves.style.move_style.width: can not be negative, got -1",
+		"last_move.style.arrow_style.factor: must be positive, got 0",
+	}
+	if strings.Join(ve.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("RenderWithContext() got problems:\n%v\nwant:\n%v", strings.Join(ve.Problems, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateContextStylesInheritedLabelFontSize(t *testing.T) {
+	t.Parallel()
+
+	// A label font size of 0 uses the label font size of the default move style
+	imager := NewImager()
+	tactics := imager.settings.TacticsStyle
+	tactics.PinStyle.LabelFontSize = 0
+	lastMove := imager.settings.LastMoveStyle
+	lastMove.ArrowStyle.LabelFontSize = 0
+
+	ctx := imager.NewContext("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1").
+		ShowTacticsWithStyle(&tactics).
+		AddLastMoveFromPositionsWithStyle("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", &lastMove)
+	if _, err := imager.RenderWithContext(ctx); err != nil {
+		t.Errorf("RenderWithContext() failed : %v", err)
+	}
+
+	// The settings have no style to inherit from
+	s := NewImager().settings
+	s.TacticsStyle.PinStyle.LabelFontSize = 0
+	if err := s.Validate(); err == nil || !strings.Contains(err.Error(), "tactics_style.pin_style.label_font_size") {
+		t.Errorf("Validate() got = %v, want a label_font_size problem", err)
+	}
+}
@@ -0,0 +1,392 @@
+package chessImager
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// This file contains a small lossless WebP (VP8L) encoder. It uses the subtract
+// green transform, and backward references to the previous pixel and to the
+// pixel above, which compresses the large flat areas of a chess board well.
+// See https://developers.google.com/speed/webp/docs/webp_lossless_bitstream_specification
+
+const (
+	webpMaxSize           = 1 << 14
+	webpMinMatchLength    = 3
+	webpMaxMatchLength    = 4096
+	webpNumLengthCodes    = 24
+	webpNumDistanceCodes  = 40
+	webpMaxCodeLength     = 15
+	webpMaxCodeLengthCode = 7
+
+	// Plane codes for the pixel above (0,1) and the pixel to the left (1,0)
+	webpPlaneCodeAbove = 1
+	webpPlaneCodeLeft  = 2
+)
+
+var webpCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// webpSymbol is either a literal ARGB pixel, or a backward reference
+// of length pixels, using the plane code distance.
+type webpSymbol struct {
+	argb     uint32
+	length   int
+	distance int
+}
+
+// encodeWebP encodes an image as a lossless WebP image.
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > webpMaxSize || height > webpMaxSize {
+		return errors.New("invalid image size for webp, must be between 1x1 and 16384x16384")
+	}
+
+	// Convert to ARGB, and apply the subtract green transform
+	pixels := make([]uint32, width*height)
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			if c.A != 0xff {
+				hasAlpha = true
+			}
+			r, bl := c.R-c.G, c.B-c.G
+			pixels[y*width+x] = uint32(c.A)<<24 | uint32(r)<<16 | uint32(c.G)<<8 | uint32(bl)
+		}
+	}
+
+	symbols := webpBackwardReferences(pixels, width)
+
+	// Histograms for green (+ length prefixes), red, blue, alpha and distance prefixes
+	histograms := [5][]int{
+		make([]int, 256+webpNumLengthCodes),
+		make([]int, 256),
+		make([]int, 256),
+		make([]int, 256),
+		make([]int, webpNumDistanceCodes),
+	}
+	for _, s := range symbols {
+		if s.length == 0 {
+			histograms[0][s.argb>>8&0xff]++
+			histograms[1][s.argb>>16&0xff]++
+			histograms[2][s.argb&0xff]++
+			histograms[3][s.argb>>24]++
+		} else {
+			lp, _, _ := webpPrefixEncode(s.length)
+			dp, _, _ := webpPrefixEncode(s.distance)
+			histograms[0][256+lp]++
+			histograms[4][dp]++
+		}
+	}
+
+	bw := &webpBitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // Version
+
+	bw.write(1, 1) // Transform present
+	bw.write(2, 2) // Subtract green transform
+	bw.write(0, 1) // No more transforms
+	bw.write(0, 1) // No color cache
+	bw.write(0, 1) // No meta prefix codes
+
+	var codes [5]webpHuffmanCode
+	for i, h := range histograms {
+		codes[i] = newWebpHuffmanCode(h, webpMaxCodeLength)
+		codes[i].writeCode(bw)
+	}
+
+	for _, s := range symbols {
+		if s.length == 0 {
+			codes[0].writeSymbol(bw, int(s.argb>>8&0xff))
+			codes[1].writeSymbol(bw, int(s.argb>>16&0xff))
+			codes[2].writeSymbol(bw, int(s.argb&0xff))
+			codes[3].writeSymbol(bw, int(s.argb>>24))
+		} else {
+			lp, lBits, lExtra := webpPrefixEncode(s.length)
+			codes[0].writeSymbol(bw, 256+lp)
+			bw.write(lExtra, lBits)
+			dp, dBits, dExtra := webpPrefixEncode(s.distance)
+			codes[4].writeSymbol(bw, dp)
+			bw.write(dExtra, dBits)
+		}
+	}
+	data := bw.bytes()
+
+	// RIFF container
+	padding := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if padding == 1 {
+		data = append(data, 0)
+	}
+	_, err := w.Write(data)
+
+	return err
+}
+
+// webpBackwardReferences greedily replaces runs of pixels that are equal to the
+// pixel to the left, or to the pixel above, with backward references.
+func webpBackwardReferences(pixels []uint32, width int) []webpSymbol {
+	var symbols []webpSymbol
+	matchLength := func(p, dist int) int {
+		n := 0
+		for p+n < len(pixels) && n < webpMaxMatchLength && pixels[p+n] == pixels[p+n-dist] {
+			n++
+		}
+		return n
+	}
+
+	for p := 0; p < len(pixels); {
+		left, above := 0, 0
+		if p >= 1 {
+			left = matchLength(p, 1)
+		}
+		if p >= width {
+			above = matchLength(p, width)
+		}
+
+		switch {
+		case left >= webpMinMatchLength && left >= above:
+			symbols = append(symbols, webpSymbol{length: left, distance: webpPlaneCodeLeft})
+			p += left
+		case above >= webpMinMatchLength:
+			symbols = append(symbols, webpSymbol{length: above, distance: webpPlaneCodeAbove})
+			p += above
+		default:
+			symbols = append(symbols, webpSymbol{argb: pixels[p]})
+			p++
+		}
+	}
+
+	return symbols
+}
+
+// webpPrefixEncode returns the prefix code, the number of extra bits and the
+// extra bits value, for a length or a distance value (>= 1).
+func webpPrefixEncode(v int) (int, uint, uint32) {
+	d := v - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	h := 0
+	for d>>(h+1) != 0 {
+		h++
+	}
+	second := (d >> (h - 1)) & 1
+	extraBits := uint(h - 1)
+
+	return 2*h + second, extraBits, uint32(d) & (1<<extraBits - 1)
+}
+
+// webpHuffmanCode is a canonical huffman code.
+type webpHuffmanCode struct {
+	lengths []int
+	codes   []uint32
+	used    []int // The symbols with a non-zero length
+}
+
+// newWebpHuffmanCode creates a canonical huffman code for the histogram,
+// where no code is longer than maxLength bits.
+func newWebpHuffmanCode(histogram []int, maxLength int) webpHuffmanCode {
+	h := webpHuffmanCode{lengths: huffmanCodeLengths(histogram, maxLength)}
+	for s, l := range h.lengths {
+		if l > 0 {
+			h.used = append(h.used, s)
+		}
+	}
+
+	// Canonical codes, the same way as DEFLATE
+	count := make([]uint32, maxLength+1)
+	for _, l := range h.lengths {
+		count[l]++
+	}
+	count[0] = 0
+	next := make([]uint32, maxLength+2)
+	code := uint32(0)
+	for l := 1; l <= maxLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	h.codes = make([]uint32, len(h.lengths))
+	for s, l := range h.lengths {
+		if l > 0 {
+			h.codes[s] = next[l]
+			next[l]++
+		}
+	}
+
+	return h
+}
+
+// writeCode writes the code lengths of the huffman code to the bit stream.
+func (h webpHuffmanCode) writeCode(bw *webpBitWriter) {
+	switch {
+	case len(h.used) == 0:
+		// Simple code, with one symbol (0)
+		bw.write(1, 1)
+		bw.write(0, 1)
+		bw.write(0, 1)
+		bw.write(0, 1)
+		return
+	case len(h.used) <= 2 && h.used[len(h.used)-1] < 256:
+		// Simple code, with one or two 8-bit symbols
+		bw.write(1, 1)
+		bw.write(uint32(len(h.used)-1), 1)
+		if h.used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(h.used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(h.used[0]), 8)
+		}
+		if len(h.used) == 2 {
+			bw.write(uint32(h.used[1]), 8)
+		}
+		return
+	}
+
+	// Normal code, where the code lengths are huffman coded themselves
+	bw.write(0, 1)
+	histogram := make([]int, len(webpCodeLengthCodeOrder))
+	for _, l := range h.lengths {
+		histogram[l]++
+	}
+	lengthCode := newWebpHuffmanCode(histogram, webpMaxCodeLengthCode)
+
+	count := len(webpCodeLengthCodeOrder)
+	for count > 4 && lengthCode.lengths[webpCodeLengthCodeOrder[count-1]] == 0 {
+		count--
+	}
+	bw.write(uint32(count-4), 4)
+	for _, s := range webpCodeLengthCodeOrder[:count] {
+		bw.write(uint32(lengthCode.lengths[s]), 3)
+	}
+
+	bw.write(0, 1) // Code lengths for all symbols follow
+	for _, l := range h.lengths {
+		lengthCode.writeSymbol(bw, l)
+	}
+}
+
+// writeSymbol writes a symbol to the bit stream. A code with only
+// one symbol uses zero bits.
+func (h webpHuffmanCode) writeSymbol(bw *webpBitWriter, symbol int) {
+	if len(h.used) <= 1 {
+		return
+	}
+	l := h.lengths[symbol]
+	// Huffman codes are stored with the most significant bit first
+	code, reversed := h.codes[symbol], uint32(0)
+	for i := 0; i < l; i++ {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	bw.write(reversed, uint(l))
+}
+
+// huffmanCodeLengths returns the huffman code lengths for a histogram, where no
+// length is longer than maxLength. If needed, the smallest counts are raised
+// until the code lengths fit. A histogram with one symbol gets length 1.
+func huffmanCodeLengths(histogram []int, maxLength int) []int {
+	type node struct {
+		count       int
+		symbol      int
+		left, right int
+	}
+
+	lengths := make([]int, len(histogram))
+	for minCount := 1; ; minCount *= 2 {
+		var nodes []node
+		for s, c := range histogram {
+			if c > 0 {
+				nodes = append(nodes, node{count: max(c, minCount), symbol: s, left: -1, right: -1})
+			}
+		}
+		switch len(nodes) {
+		case 0:
+			return lengths
+		case 1:
+			lengths[nodes[0].symbol] = 1
+			return lengths
+		}
+
+		// Build the tree using two queues, the leaves sorted by count,
+		// and the internal nodes (which are created in sorted order).
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].count < nodes[j].count })
+		leaves := len(nodes)
+		leaf, internal := 0, leaves
+		pop := func() int {
+			if leaf < leaves && (internal >= len(nodes) || nodes[leaf].count <= nodes[internal].count) {
+				leaf++
+				return leaf - 1
+			}
+			internal++
+			return internal - 1
+		}
+		for n := 0; n < leaves-1; n++ {
+			a, b := pop(), pop()
+			nodes = append(nodes, node{count: nodes[a].count + nodes[b].count, symbol: -1, left: a, right: b})
+		}
+
+		// Calculate the depth of every leaf
+		depths := make([]int, len(nodes))
+		maxDepth := 0
+		for n := len(nodes) - 1; n >= leaves; n-- {
+			depths[nodes[n].left] = depths[n] + 1
+			depths[nodes[n].right] = depths[n] + 1
+		}
+		for n := 0; n < leaves; n++ {
+			maxDepth = max(maxDepth, depths[n])
+		}
+		if maxDepth > maxLength {
+			continue
+		}
+		for n := 0; n < leaves; n++ {
+			lengths[nodes[n].symbol] = depths[n]
+		}
+		return lengths
+	}
+}
+
+// webpBitWriter writes bits, least significant bit first.
+type webpBitWriter struct {
+	buf  []byte
+	acc  uint64
+	bits uint
+}
+
+func (b *webpBitWriter) write(v uint32, n uint) {
+	b.acc |= uint64(v) << b.bits
+	b.bits += n
+	for b.bits >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.bits -= 8
+	}
+}
+
+func (b *webpBitWriter) bytes() []byte {
+	if b.bits > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.bits = 0, 0
+	}
+	return b.buf
+}